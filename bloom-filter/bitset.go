@@ -0,0 +1,80 @@
+package bloomfilter
+
+import (
+	"math/bits"
+	"sync/atomic"
+)
+
+// bitset is a fixed size set of bits packed 64 to a word. Bit i lives in
+// word i/64 at position i%64.
+type bitset []uint64
+
+func newBitset(m uint64) bitset {
+	return make(bitset, (m+63)/64)
+}
+
+func (b bitset) set(i uint64) {
+	b[i>>6] |= 1 << (i & 63)
+}
+
+func (b bitset) test(i uint64) bool {
+	return b[i>>6]&(1<<(i&63)) != 0
+}
+
+// count returns the number of set bits.
+func (b bitset) count() uint64 {
+	var n int
+	for _, word := range b {
+		n += bits.OnesCount64(word)
+	}
+	return uint64(n)
+}
+
+func (b bitset) clear() {
+	clear(b)
+}
+
+// atomicBitset is a bitset whose bits can be set and tested from many
+// goroutines at once.
+type atomicBitset []atomic.Uint64
+
+func newAtomicBitset(m uint64) atomicBitset {
+	return make(atomicBitset, (m+63)/64)
+}
+
+func (b atomicBitset) set(i uint64) {
+	mask := uint64(1) << (i & 63)
+	word := &b[i>>6]
+	// Most bits of a busy filter are already set; skip the write then.
+	if word.Load()&mask == 0 {
+		word.Or(mask)
+	}
+}
+
+func (b atomicBitset) test(i uint64) bool {
+	return b[i>>6].Load()&(1<<(i&63)) != 0
+}
+
+func (b atomicBitset) count() uint64 {
+	var n int
+	for i := range b {
+		n += bits.OnesCount64(b[i].Load())
+	}
+	return uint64(n)
+}
+
+func (b atomicBitset) clear() {
+	for i := range b {
+		b[i].Store(0)
+	}
+}
+
+// snapshot copies the bits into a plain bitset. Bits set while it runs may
+// or may not be included.
+func (b atomicBitset) snapshot() bitset {
+	words := make(bitset, len(b))
+	for i := range b {
+		words[i] = b[i].Load()
+	}
+	return words
+}
@@ -0,0 +1,97 @@
+package bloomfilter
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBitset(t *testing.T) {
+	b := newBitset(130)
+	if len(b) != 3 {
+		t.Fatalf("len = %d, want 3 words for 130 bits", len(b))
+	}
+
+	for _, i := range []uint64{0, 63, 64, 129} {
+		b.set(i)
+	}
+	for i := range uint64(130) {
+		want := i == 0 || i == 63 || i == 64 || i == 129
+		if b.test(i) != want {
+			t.Errorf("test(%d) = %v, want %v", i, b.test(i), want)
+		}
+	}
+	if b.count() != 4 {
+		t.Errorf("count = %d, want 4", b.count())
+	}
+
+	b.clear()
+	if b.count() != 0 {
+		t.Errorf("count after clear = %d, want 0", b.count())
+	}
+}
+
+// benchBits is the size of a filter for 1M items at 1% FPR.
+const benchBits = 9585059
+
+// The []bool benchmarks measure the storage the filter used before bits
+// were packed, for comparison.
+
+func BenchmarkStorageAlloc(b *testing.B) {
+	b.Run("bool", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			_ = make([]bool, benchBits)
+		}
+	})
+	b.Run("packed", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			_ = newBitset(benchBits)
+		}
+	})
+}
+
+func BenchmarkStorageSetTest(b *testing.B) {
+	idx := make([]uint64, 4096)
+	for i := range idx {
+		idx[i] = uint64(rand.Int63n(benchBits))
+	}
+
+	b.Run("bool", func(b *testing.B) {
+		bits := make([]bool, benchBits)
+		for i := range b.N {
+			j := idx[i%len(idx)]
+			bits[j] = true
+			_ = bits[j]
+		}
+	})
+	b.Run("packed", func(b *testing.B) {
+		bits := newBitset(benchBits)
+		for i := range b.N {
+			j := idx[i%len(idx)]
+			bits.set(j)
+			_ = bits.test(j)
+		}
+	})
+}
+
+func BenchmarkStorageCount(b *testing.B) {
+	b.Run("bool", func(b *testing.B) {
+		bits := make([]bool, benchBits)
+		for range b.N {
+			n := 0
+			for _, bit := range bits {
+				if bit {
+					n++
+				}
+			}
+			_ = n
+		}
+	})
+	b.Run("packed", func(b *testing.B) {
+		bits := newBitset(benchBits)
+		for range b.N {
+			_ = bits.count()
+		}
+	})
+}
@@ -1,11 +1,9 @@
 package bloomfilter
 
 import (
-	"hash"
-	"math"
+	"fmt"
 	"math/rand"
-
-	"github.com/spaolacci/murmur3"
+	"slices"
 )
 
 const (
@@ -13,38 +11,93 @@ const (
 )
 
 type BloomFilter struct {
-	bitSet        []bool
-	hashFunctions []hash.Hash64
-	hashCount     int64
+	bits      bitset
+	m         uint64
+	hashCount int64
+	scheme    HashScheme
+	seeds     []uint32
 }
 
+// NewBloomFilter returns a filter for n items at false positive rate p,
+// with randomly seeded hash functions. It panics unless n is positive and p
+// is strictly between 0 and 1; NewBloomFilterWithOptions returns an error
+// instead.
 func NewBloomFilter(p float64, n int) *BloomFilter {
-	m := -float64(n) * math.Log(p) / (math.Log(2) * math.Log(2))
-	m_int := int64(math.Ceil(m))
-	k := m / float64(n) * math.Log(2)
-	k_int := int64(math.Ceil(k))
-	hashFunctions := make([]hash.Hash64, k_int)
-	for i := range k_int {
-		hashFunctions[i] = murmur3.New64WithSeed(uint32(rand.Intn(MAX_SAFE_PRIME)))
+	m, k := optimalParams(p, n)
+	seeds := make([]uint32, k)
+	for i := range seeds {
+		seeds[i] = uint32(rand.Intn(MAX_SAFE_PRIME))
+	}
+	return newBloomFilter(m, k, HashMurmur3Seeded, seeds)
+}
+
+// NewBloomFilterWithSeed is like NewBloomFilter, but derives the hash seeds
+// from seed. Filters built with the same p, n and seed set the same bits
+// for the same items, across runs and machines.
+func NewBloomFilterWithSeed(p float64, n int, seed uint64) *BloomFilter {
+	m, k := optimalParams(p, n)
+	return newBloomFilter(m, k, HashMurmur3Seeded, deriveSeeds(seed, int(k)))
+}
+
+// NewBloomFilterWithScheme is like NewBloomFilterWithSeed, but maps items
+// to bits with the given scheme. It is shorthand for
+// NewBloomFilterWithOptions that panics instead of returning an error.
+func NewBloomFilterWithScheme(p float64, n int, scheme HashScheme, seed uint64) *BloomFilter {
+	bf, err := NewBloomFilterWithOptions(Options{
+		Capacity:          n,
+		FalsePositiveRate: p,
+		HashScheme:        scheme,
+		Seed:              seed,
+	})
+	if err != nil {
+		panic(err)
 	}
+	return bf
+}
+
+// NewBloomFilterWithSeeds is like NewBloomFilter, but uses one hash function
+// per given seed, for example the Seeds of an existing filter. It panics
+// unless there are as many seeds as the filter needs hash functions.
+func NewBloomFilterWithSeeds(p float64, n int, seeds []uint32) *BloomFilter {
+	m, k := optimalParams(p, n)
+	if int64(len(seeds)) != k {
+		panic(fmt.Sprintf("bloomfilter: got %d seeds, need %d", len(seeds), k))
+	}
+	return newBloomFilter(m, k, HashMurmur3Seeded, slices.Clone(seeds))
+}
+
+func newBloomFilter(m, k int64, scheme HashScheme, seeds []uint32) *BloomFilter {
 	return &BloomFilter{
-		bitSet:        make([]bool, m_int),
-		hashFunctions: hashFunctions,
-		hashCount:     k_int,
+		bits:      newBitset(uint64(m)),
+		m:         uint64(m),
+		hashCount: k,
+		scheme:    scheme,
+		seeds:     seeds,
 	}
 }
 
 func (bf *BloomFilter) Add(item string) {
-	hashes := bf.computeHashes(item)
-	for _, hash := range hashes {
-		bf.bitSet[hash] = true
-	}
+	bf.AddBytes([]byte(item))
 }
 
 func (bf *BloomFilter) Contains(item string) bool {
-	hashes := bf.computeHashes(item)
-	for _, hash := range hashes {
-		if !bf.bitSet[hash] {
+	return bf.ContainsBytes([]byte(item))
+}
+
+// AddBytes adds data to the filter. Add(s) and AddBytes([]byte(s)) set the
+// same bits.
+func (bf *BloomFilter) AddBytes(data []byte) {
+	var buf [16]uint64
+	for _, hash := range bf.computeHashes(buf[:0], data) {
+		bf.bits.set(hash)
+	}
+}
+
+// ContainsBytes reports whether data may have been added to the filter.
+func (bf *BloomFilter) ContainsBytes(data []byte) bool {
+	var buf [16]uint64
+	for _, hash := range bf.computeHashes(buf[:0], data) {
+		if !bf.bits.test(hash) {
 			return false
 		}
 	}
@@ -52,30 +105,56 @@ func (bf *BloomFilter) Contains(item string) bool {
 }
 
 func (bf *BloomFilter) Clear() {
-	for i := range bf.bitSet {
-		bf.bitSet[i] = false
-	}
+	bf.bits.clear()
 }
 
 func (bf *BloomFilter) Size() int {
-	return len(bf.bitSet)
+	return int(bf.m)
 }
 
 func (bf *BloomFilter) HashCount() int64 {
 	return bf.hashCount
 }
 
-func (bf *BloomFilter) BitSet() []bool {
-	return bf.bitSet
+// HashScheme returns how the filter maps items to bits.
+func (bf *BloomFilter) HashScheme() HashScheme {
+	return bf.scheme
 }
 
-func (bf *BloomFilter) computeHashes(item string) []uint64 {
-	hashes := make([]uint64, bf.hashCount)
-	for i := range bf.hashCount {
-		bf.hashFunctions[i].Write([]byte(item))
-		hashes[i] = bf.hashFunctions[i].Sum64() % uint64(len(bf.bitSet))
-		bf.hashFunctions[i].Reset()
+// Seeds returns the seeds of the filter's hash functions: one per function
+// for HashMurmur3Seeded, a single one for HashMurmur3KM.
+func (bf *BloomFilter) Seeds() []uint32 {
+	return slices.Clone(bf.seeds)
+}
+
+// BitSet returns a copy of the filter's bits, one bool per bit.
+//
+// Deprecated: BitSet allocates a byte for every bit of the filter. Use Bit
+// to test single bits, Words for the packed storage or PopCount.
+func (bf *BloomFilter) BitSet() []bool {
+	bitSet := make([]bool, bf.m)
+	for i := range bf.m {
+		bitSet[i] = bf.bits.test(i)
 	}
-	return hashes
+	return bitSet
 }
 
+// Bit reports whether bit i of the filter is set.
+func (bf *BloomFilter) Bit(i int) bool {
+	return bf.bits.test(uint64(i))
+}
+
+// Words returns the filter's bits packed 64 to a word; bit i is bit i%64
+// of word i/64. The slice is shared with the filter.
+func (bf *BloomFilter) Words() []uint64 {
+	return bf.bits
+}
+
+// PopCount returns the number of set bits.
+func (bf *BloomFilter) PopCount() int {
+	return int(bf.bits.count())
+}
+
+func (bf *BloomFilter) computeHashes(dst []uint64, data []byte) []uint64 {
+	return bf.scheme.indexes(dst, bf.seeds, int(bf.hashCount), bf.m, data)
+}
@@ -52,7 +52,7 @@ func TestAddAndContains(t *testing.T) {
 
 	// Check non-added words (may have false positives, but for testing we can check a few)
 	falsePositives := 0
-	testWordsCount := (math.MaxInt64)
+	testWordsCount := 10000
 	for range testWordsCount {
 		word := randomWord(rand.Intn(10) + 1)
 		if bf.Contains(word) && !slices.Contains(addedWords, word) {
@@ -71,3 +71,98 @@ func TestClear(t *testing.T) {
 	bf.Clear()
 	assert.False(t, bf.Contains("test"))
 }
+
+func TestPackedBits(t *testing.T) {
+	bf := bloomfilter.NewBloomFilter(0.01, 1000)
+	assert.Len(t, bf.Words(), (bf.Size()+63)/64)
+	assert.Equal(t, 0, bf.PopCount())
+
+	bf.Add("test")
+	bitSet := bf.BitSet()
+	set := 0
+	for i, bit := range bitSet {
+		assert.Equal(t, bit, bf.Bit(i))
+		if bit {
+			set++
+		}
+	}
+	assert.Equal(t, set, bf.PopCount())
+	assert.LessOrEqual(t, int64(set), bf.HashCount())
+
+	bf.Clear()
+	assert.Equal(t, 0, bf.PopCount())
+}
+
+func BenchmarkNewBloomFilter(b *testing.B) {
+	b.ReportAllocs()
+	for range b.N {
+		bloomfilter.NewBloomFilter(0.01, 1000000)
+	}
+}
+
+func BenchmarkAdd(b *testing.B) {
+	bf := bloomfilter.NewBloomFilter(0.01, 1000000)
+	words := make([]string, 1024)
+	for i := range words {
+		words[i] = randomWord(10)
+	}
+	b.ResetTimer()
+	for i := range b.N {
+		bf.Add(words[i%len(words)])
+	}
+}
+
+func BenchmarkContains(b *testing.B) {
+	bf := bloomfilter.NewBloomFilter(0.01, 1000000)
+	words := make([]string, 1024)
+	for i := range words {
+		words[i] = randomWord(10)
+		if i%2 == 0 {
+			bf.Add(words[i])
+		}
+	}
+	b.ResetTimer()
+	for i := range b.N {
+		bf.Contains(words[i%len(words)])
+	}
+}
+
+func TestSeededFiltersAreReproducible(t *testing.T) {
+	a := bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 42)
+	b := bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 42)
+	assert.Equal(t, a.Seeds(), b.Seeds())
+
+	// Fixed bit positions guard against the seed derivation or hashing
+	// changing underneath filters that have already been shared.
+	a.Add("hello")
+	var set []int
+	for i := range a.Size() {
+		if a.Bit(i) {
+			set = append(set, i)
+		}
+	}
+	assert.Equal(t, []int{1725, 1834, 1999, 2122, 2257, 2261, 4764}, set)
+
+	for _, word := range []string{"hello", "seeded", "bloom"} {
+		a.Add(word)
+		b.Add(word)
+	}
+	assert.Equal(t, a.Words(), b.Words())
+
+	c := bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 43)
+	c.Add("hello")
+	assert.NotEqual(t, a.Seeds(), c.Seeds())
+}
+
+func TestNewBloomFilterWithSeeds(t *testing.T) {
+	a := bloomfilter.NewBloomFilter(0.01, 1000)
+	a.Add("hello")
+
+	b := bloomfilter.NewBloomFilterWithSeeds(0.01, 1000, a.Seeds())
+	b.Add("hello")
+	assert.Equal(t, a.Words(), b.Words())
+
+	assert.Panics(t, func() {
+		bloomfilter.NewBloomFilterWithSeeds(0.01, 1000, []uint32{1, 2})
+	})
+}
@@ -0,0 +1,127 @@
+package bloomfilter
+
+import (
+	"math/rand"
+	"slices"
+)
+
+// ConcurrentBloomFilter is a BloomFilter that is safe for concurrent use.
+// Bits are set with atomic operations on the packed words and every call
+// hashes with its own state, so Add and Contains never block each other.
+//
+// A ConcurrentBloomFilter sets the same bits as a BloomFilter with the same
+// size, hash scheme and seeds.
+type ConcurrentBloomFilter struct {
+	bits   atomicBitset
+	m      uint64
+	k      int
+	scheme HashScheme
+	seeds  []uint32
+}
+
+// NewConcurrentBloomFilter returns a concurrency-safe filter for n items at
+// false positive rate p, with randomly seeded hash functions.
+func NewConcurrentBloomFilter(p float64, n int) *ConcurrentBloomFilter {
+	m, k := optimalParams(p, n)
+	seeds := make([]uint32, k)
+	for i := range seeds {
+		seeds[i] = uint32(rand.Intn(MAX_SAFE_PRIME))
+	}
+	return newConcurrentBloomFilter(m, k, HashMurmur3Seeded, seeds)
+}
+
+// NewConcurrentBloomFilterWithSeed is like NewConcurrentBloomFilter, but
+// derives the hash seeds from seed as NewBloomFilterWithSeed does.
+func NewConcurrentBloomFilterWithSeed(p float64, n int, seed uint64) *ConcurrentBloomFilter {
+	m, k := optimalParams(p, n)
+	return newConcurrentBloomFilter(m, k, HashMurmur3Seeded, deriveSeeds(seed, int(k)))
+}
+
+func newConcurrentBloomFilter(m, k int64, scheme HashScheme, seeds []uint32) *ConcurrentBloomFilter {
+	return &ConcurrentBloomFilter{
+		bits:   newAtomicBitset(uint64(m)),
+		m:      uint64(m),
+		k:      int(k),
+		scheme: scheme,
+		seeds:  seeds,
+	}
+}
+
+// Concurrent returns a concurrency-safe copy of the filter.
+func (bf *BloomFilter) Concurrent() *ConcurrentBloomFilter {
+	cbf := newConcurrentBloomFilter(int64(bf.m), bf.hashCount, bf.scheme, slices.Clone(bf.seeds))
+	for i, word := range bf.bits {
+		cbf.bits[i].Store(word)
+	}
+	return cbf
+}
+
+func (bf *ConcurrentBloomFilter) Add(item string) {
+	bf.AddBytes([]byte(item))
+}
+
+func (bf *ConcurrentBloomFilter) Contains(item string) bool {
+	return bf.ContainsBytes([]byte(item))
+}
+
+// AddBytes adds data to the filter, setting the same bits as Add would for
+// the same bytes.
+func (bf *ConcurrentBloomFilter) AddBytes(data []byte) {
+	var buf [16]uint64
+	for _, index := range bf.indexes(buf[:0], data) {
+		bf.bits.set(index)
+	}
+}
+
+// ContainsBytes reports whether data may have been added to the filter.
+func (bf *ConcurrentBloomFilter) ContainsBytes(data []byte) bool {
+	var buf [16]uint64
+	for _, index := range bf.indexes(buf[:0], data) {
+		if !bf.bits.test(index) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clear unsets every bit. Items added while Clear runs may be partly
+// cleared.
+func (bf *ConcurrentBloomFilter) Clear() {
+	bf.bits.clear()
+}
+
+func (bf *ConcurrentBloomFilter) Size() int {
+	return int(bf.m)
+}
+
+func (bf *ConcurrentBloomFilter) HashCount() int64 {
+	return int64(bf.k)
+}
+
+// HashScheme returns how the filter maps items to bits.
+func (bf *ConcurrentBloomFilter) HashScheme() HashScheme {
+	return bf.scheme
+}
+
+// Seeds returns the seeds of the filter's hash functions.
+func (bf *ConcurrentBloomFilter) Seeds() []uint32 {
+	return slices.Clone(bf.seeds)
+}
+
+// PopCount returns the number of set bits.
+func (bf *ConcurrentBloomFilter) PopCount() int {
+	return int(bf.bits.count())
+}
+
+// Snapshot returns a plain BloomFilter holding the filter's current bits,
+// for example to serialize it. Items added while Snapshot runs may or may
+// not be included.
+func (bf *ConcurrentBloomFilter) Snapshot() *BloomFilter {
+	snapshot := newBloomFilter(int64(bf.m), int64(bf.k), bf.scheme, slices.Clone(bf.seeds))
+	snapshot.bits = bf.bits.snapshot()
+	return snapshot
+}
+
+func (bf *ConcurrentBloomFilter) indexes(dst []uint64, data []byte) []uint64 {
+	return bf.scheme.indexes(dst, bf.seeds, bf.k, bf.m, data)
+}
@@ -0,0 +1,96 @@
+package bloomfilter_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	bloomfilter "dsalgo/bloom-filter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentMatchesBloomFilter(t *testing.T) {
+	bf := bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 7)
+	cbf := bloomfilter.NewConcurrentBloomFilterWithSeed(0.01, 1000, 7)
+	for _, word := range []string{"alpha", "bravo", "charlie"} {
+		bf.Add(word)
+		cbf.Add(word)
+	}
+
+	assert.Equal(t, bf.Size(), cbf.Size())
+	assert.Equal(t, bf.HashCount(), cbf.HashCount())
+	assert.Equal(t, bf.Words(), cbf.Snapshot().Words())
+	assert.Equal(t, bf.PopCount(), cbf.PopCount())
+	assert.Equal(t, bf.Words(), bf.Concurrent().Snapshot().Words())
+
+	cbf.Clear()
+	assert.Equal(t, 0, cbf.PopCount())
+	assert.False(t, cbf.Contains("alpha"))
+}
+
+// TestConcurrentStress is meant to be run with -race.
+func TestConcurrentStress(t *testing.T) {
+	const (
+		writers      = 8
+		readers      = 8
+		perGoroutine = 2000
+	)
+	cbf := bloomfilter.NewConcurrentBloomFilter(0.01, writers*perGoroutine)
+
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perGoroutine {
+				word := fmt.Sprintf("w%d-%d", w, i)
+				cbf.Add(word)
+				if !cbf.Contains(word) {
+					t.Errorf("false negative for %s right after adding it", word)
+					return
+				}
+			}
+		}()
+	}
+	for r := range readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perGoroutine {
+				cbf.Contains(fmt.Sprintf("w%d-%d", r%writers, i))
+				if i%500 == 0 {
+					cbf.PopCount()
+					cbf.Snapshot()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	snapshot := cbf.Snapshot()
+	for w := range writers {
+		for i := range perGoroutine {
+			word := fmt.Sprintf("w%d-%d", w, i)
+			if !cbf.Contains(word) || !snapshot.Contains(word) {
+				t.Fatalf("false negative for %s", word)
+			}
+		}
+	}
+}
+
+func BenchmarkConcurrentAdd(b *testing.B) {
+	cbf := bloomfilter.NewConcurrentBloomFilter(0.01, 1000000)
+	words := make([]string, 1024)
+	for i := range words {
+		words[i] = randomWord(10)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cbf.Add(words[i%len(words)])
+			i++
+		}
+	})
+}
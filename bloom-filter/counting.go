@@ -0,0 +1,201 @@
+package bloomfilter
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// DefaultCounterBits is the counter width NewCountingBloomFilter uses. Four
+// bits make overflow vanishingly unlikely at the optimal k (Fan et al.).
+const DefaultCounterBits = 4
+
+// CountingBloomFilter is a Bloom filter with a small counter in place of
+// each bit, so items can be removed again. A counter that reaches its
+// maximum saturates: it stays there on later adds and removes, since its
+// true count is no longer known, so items that hashed to it may linger as
+// false positives but never turn into false negatives.
+type CountingBloomFilter struct {
+	counters  counters
+	m         uint64
+	hashCount int64
+	scheme    HashScheme
+	seeds     []uint32
+}
+
+// NewCountingBloomFilter returns a counting filter for n items at false
+// positive rate p, with DefaultCounterBits wide counters and randomly
+// seeded hash functions.
+func NewCountingBloomFilter(p float64, n int) *CountingBloomFilter {
+	return NewCountingBloomFilterWithCounterBits(p, n, DefaultCounterBits, rand.Uint64())
+}
+
+// NewCountingBloomFilterWithCounterBits returns a counting filter with
+// counters of the given width, which must be 2, 4, 8 or 16, and hash seeds
+// derived from seed. It panics on any other width.
+func NewCountingBloomFilterWithCounterBits(p float64, n int, counterBits int, seed uint64) *CountingBloomFilter {
+	switch counterBits {
+	case 2, 4, 8, 16:
+	default:
+		panic(fmt.Sprintf("bloomfilter: unsupported counter width %d", counterBits))
+	}
+	m, k := optimalParams(p, n)
+	return &CountingBloomFilter{
+		counters:  newCounters(uint64(m), uint(counterBits)),
+		m:         uint64(m),
+		hashCount: k,
+		scheme:    HashMurmur3Seeded,
+		seeds:     deriveSeeds(seed, int(k)),
+	}
+}
+
+func (cbf *CountingBloomFilter) Add(item string) {
+	cbf.AddBytes([]byte(item))
+}
+
+// AddBytes adds data, incrementing each of its k counters.
+func (cbf *CountingBloomFilter) AddBytes(data []byte) {
+	for _, index := range cbf.indexes(data) {
+		cbf.counters.increment(index)
+	}
+}
+
+// Remove removes an item added earlier. It reports false, changing
+// nothing, if the item is certainly not in the filter. Removing an item
+// that was never added can cause false negatives for other items.
+func (cbf *CountingBloomFilter) Remove(item string) bool {
+	return cbf.RemoveBytes([]byte(item))
+}
+
+// RemoveBytes removes data added earlier, like Remove.
+func (cbf *CountingBloomFilter) RemoveBytes(data []byte) bool {
+	indexes := cbf.indexes(data)
+	for _, index := range indexes {
+		if cbf.counters.get(index) == 0 {
+			return false
+		}
+	}
+	for _, index := range indexes {
+		cbf.counters.decrement(index)
+	}
+	return true
+}
+
+func (cbf *CountingBloomFilter) Contains(item string) bool {
+	return cbf.ContainsBytes([]byte(item))
+}
+
+// ContainsBytes reports whether data may have been added to the filter.
+func (cbf *CountingBloomFilter) ContainsBytes(data []byte) bool {
+	for _, index := range cbf.indexes(data) {
+		if cbf.counters.get(index) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (cbf *CountingBloomFilter) Clear() {
+	clear(cbf.counters.words)
+}
+
+func (cbf *CountingBloomFilter) Size() int {
+	return int(cbf.m)
+}
+
+func (cbf *CountingBloomFilter) HashCount() int64 {
+	return cbf.hashCount
+}
+
+// HashScheme returns how the filter maps items to counters.
+func (cbf *CountingBloomFilter) HashScheme() HashScheme {
+	return cbf.scheme
+}
+
+// Seeds returns the seeds of the filter's hash functions.
+func (cbf *CountingBloomFilter) Seeds() []uint32 {
+	return slices.Clone(cbf.seeds)
+}
+
+// CounterBits returns the width of each counter.
+func (cbf *CountingBloomFilter) CounterBits() int {
+	return int(cbf.counters.width)
+}
+
+// Saturated returns the number of counters stuck at their maximum.
+func (cbf *CountingBloomFilter) Saturated() int {
+	n := 0
+	for i := range cbf.m {
+		if cbf.counters.get(i) == cbf.counters.max {
+			n++
+		}
+	}
+	return n
+}
+
+// BloomFilter collapses the filter into a plain BloomFilter with a bit set
+// for every non-zero counter. The result answers Contains the same way in
+// a fraction of the space, for shipping to readers that never remove.
+func (cbf *CountingBloomFilter) BloomFilter() *BloomFilter {
+	bf := newBloomFilter(int64(cbf.m), cbf.hashCount, cbf.scheme, slices.Clone(cbf.seeds))
+	for i := range cbf.m {
+		if cbf.counters.get(i) != 0 {
+			bf.bits.set(i)
+		}
+	}
+	return bf
+}
+
+func (cbf *CountingBloomFilter) indexes(data []byte) []uint64 {
+	return cbf.scheme.indexes(make([]uint64, 0, cbf.hashCount), cbf.seeds, int(cbf.hashCount), cbf.m, data)
+}
+
+// counters is an array of fixed width unsigned counters packed into words.
+// The width divides 64, so no counter spans two words. CuckooFilter keeps
+// its fingerprints in one too.
+type counters struct {
+	words []uint64
+	width uint
+	max   uint64
+}
+
+func newCounters(n uint64, width uint) counters {
+	c := counters{width: width, max: 1<<width - 1}
+	c.words = make([]uint64, c.wordsFor(n))
+	return c
+}
+
+// wordsFor returns the number of words n counters take.
+func (c counters) wordsFor(n uint64) uint64 {
+	perWord := uint64(64 / c.width)
+	return (n + perWord - 1) / perWord
+}
+
+func (c counters) locate(i uint64) (word uint64, shift uint) {
+	perWord := uint64(64 / c.width)
+	return i / perWord, uint(i%perWord) * c.width
+}
+
+func (c counters) get(i uint64) uint64 {
+	word, shift := c.locate(i)
+	return c.words[word] >> shift & c.max
+}
+
+func (c counters) set(i, v uint64) {
+	word, shift := c.locate(i)
+	c.words[word] = c.words[word]&^(c.max<<shift) | (v&c.max)<<shift
+}
+
+func (c counters) increment(i uint64) {
+	word, shift := c.locate(i)
+	if c.words[word]>>shift&c.max != c.max {
+		c.words[word] += 1 << shift
+	}
+}
+
+func (c counters) decrement(i uint64) {
+	word, shift := c.locate(i)
+	if v := c.words[word] >> shift & c.max; v != 0 && v != c.max {
+		c.words[word] -= 1 << shift
+	}
+}
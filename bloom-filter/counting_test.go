@@ -0,0 +1,73 @@
+package bloomfilter_test
+
+import (
+	"fmt"
+	"testing"
+
+	bloomfilter "dsalgo/bloom-filter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountingAddRemove(t *testing.T) {
+	cbf := bloomfilter.NewCountingBloomFilter(0.01, 1000)
+	assert.Equal(t, bloomfilter.DefaultCounterBits, cbf.CounterBits())
+
+	for i := range 500 {
+		cbf.Add(fmt.Sprintf("item-%d", i))
+	}
+	for i := range 250 {
+		assert.True(t, cbf.Remove(fmt.Sprintf("item-%d", i)))
+	}
+	for i := 250; i < 500; i++ {
+		assert.True(t, cbf.Contains(fmt.Sprintf("item-%d", i)), "removing other items caused a false negative")
+	}
+
+	removed := 0
+	for i := range 250 {
+		if !cbf.Contains(fmt.Sprintf("item-%d", i)) {
+			removed++
+		}
+	}
+	assert.Greater(t, removed, 240)
+
+	assert.False(t, cbf.Remove("never-added"))
+
+	cbf.Clear()
+	assert.False(t, cbf.Contains("item-300"))
+}
+
+func TestCountingSaturation(t *testing.T) {
+	cbf := bloomfilter.NewCountingBloomFilterWithCounterBits(0.01, 100, 2, 1)
+
+	// A 2 bit counter saturates at 3; adding the item four times and
+	// removing it four times must leave it in place rather than wrapping.
+	for range 4 {
+		cbf.Add("hot")
+	}
+	assert.Equal(t, int(cbf.HashCount()), cbf.Saturated())
+	for range 4 {
+		cbf.Remove("hot")
+	}
+	assert.True(t, cbf.Contains("hot"))
+
+	assert.Panics(t, func() {
+		bloomfilter.NewCountingBloomFilterWithCounterBits(0.01, 100, 3, 1)
+	})
+}
+
+func TestCountingCollapse(t *testing.T) {
+	cbf := bloomfilter.NewCountingBloomFilterWithCounterBits(0.01, 1000, 4, 9)
+	bf := bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 9)
+	for _, word := range []string{"alpha", "bravo", "charlie"} {
+		cbf.Add(word)
+		bf.Add(word)
+	}
+	cbf.Add("delta")
+	cbf.Remove("delta")
+
+	collapsed := cbf.BloomFilter()
+	assert.Equal(t, bf.Seeds(), collapsed.Seeds())
+	assert.Equal(t, bf.Words(), collapsed.Words())
+	assert.True(t, collapsed.Contains("bravo"))
+}
@@ -0,0 +1,251 @@
+package bloomfilter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+)
+
+// ErrCuckooFull is returned by CuckooFilter.Add when an item finds no free
+// slot within the kick-out limit. The filter is left as it was.
+var ErrCuckooFull = errors.New("bloomfilter: cuckoo filter is full")
+
+const (
+	DefaultFingerprintBits = 16
+	DefaultBucketSize      = 4
+	DefaultMaxKicks        = 500
+
+	// cuckooLoadFactor is the occupancy a filter is sized for; with four
+	// slots per bucket inserts rarely fail below 95%.
+	cuckooLoadFactor = 0.95
+)
+
+// CuckooFilter is a cuckoo filter (Fan et al., "Cuckoo Filter: Practically
+// Better Than Bloom"). It stores a short fingerprint of each item in one of
+// two candidate buckets, so unlike a BloomFilter it supports Delete, and at
+// low false positive rates it takes less space. The false positive rate is
+// at most 2*BucketSize / 2^FingerprintBits.
+type CuckooFilter struct {
+	slots      counters // BucketSize fingerprints per bucket, 0 is empty
+	buckets    uint64   // a power of two
+	bucketSize uint64
+	maxKicks   int
+	seed       uint32
+	count      int
+	rng        *rand.Rand
+}
+
+// CuckooOptions configures NewCuckooFilterWithOptions.
+type CuckooOptions struct {
+	// Capacity is the number of items the filter must hold.
+	Capacity int
+	// FingerprintBits is 4, 8, 16 or 32; zero means DefaultFingerprintBits.
+	FingerprintBits int
+	// BucketSize is the number of fingerprints per bucket, 1 to 8; zero
+	// means DefaultBucketSize.
+	BucketSize int
+	// MaxKicks bounds how many fingerprints an Add may relocate; zero
+	// means DefaultMaxKicks.
+	MaxKicks int
+	// Seed seeds the hash and the choice of fingerprints to relocate.
+	Seed uint64
+}
+
+// NewCuckooFilter returns a cuckoo filter for capacity items with the
+// default fingerprint and bucket sizes. It panics unless capacity is
+// positive.
+func NewCuckooFilter(capacity int) *CuckooFilter {
+	cf, err := NewCuckooFilterWithOptions(CuckooOptions{Capacity: capacity, Seed: rand.Uint64()})
+	if err != nil {
+		panic(err)
+	}
+	return cf
+}
+
+// NewCuckooFilterWithOptions returns a cuckoo filter configured by opts, or
+// an error wrapping ErrInvalidOptions.
+func NewCuckooFilterWithOptions(opts CuckooOptions) (*CuckooFilter, error) {
+	if opts.FingerprintBits == 0 {
+		opts.FingerprintBits = DefaultFingerprintBits
+	}
+	if opts.BucketSize == 0 {
+		opts.BucketSize = DefaultBucketSize
+	}
+	if opts.MaxKicks == 0 {
+		opts.MaxKicks = DefaultMaxKicks
+	}
+
+	if opts.Capacity <= 0 {
+		return nil, fmt.Errorf("%w: capacity %d is not positive", ErrInvalidOptions, opts.Capacity)
+	}
+	switch opts.FingerprintBits {
+	case 4, 8, 16, 32:
+	default:
+		return nil, fmt.Errorf("%w: unsupported fingerprint size %d", ErrInvalidOptions, opts.FingerprintBits)
+	}
+	if opts.BucketSize < 1 || opts.BucketSize > 8 {
+		return nil, fmt.Errorf("%w: bucket size %d is not between 1 and 8", ErrInvalidOptions, opts.BucketSize)
+	}
+	if opts.MaxKicks < 0 {
+		return nil, fmt.Errorf("%w: negative kick limit %d", ErrInvalidOptions, opts.MaxKicks)
+	}
+
+	// Round the bucket count up to a power of two, see altIndex.
+	wanted := math.Ceil(float64(opts.Capacity) / cuckooLoadFactor / float64(opts.BucketSize))
+	if wanted > maxBits/float64(2*opts.BucketSize*opts.FingerprintBits) {
+		return nil, fmt.Errorf("%w: capacity %d is too large", ErrInvalidOptions, opts.Capacity)
+	}
+	buckets := uint64(1) << bits.Len64(uint64(wanted)-1)
+
+	return &CuckooFilter{
+		slots:      newCounters(buckets*uint64(opts.BucketSize), uint(opts.FingerprintBits)),
+		buckets:    buckets,
+		bucketSize: uint64(opts.BucketSize),
+		maxKicks:   opts.MaxKicks,
+		seed:       deriveSeeds(opts.Seed, 1)[0],
+		rng:        rand.New(rand.NewSource(int64(opts.Seed))),
+	}, nil
+}
+
+func (cf *CuckooFilter) Add(item string) error {
+	return cf.AddBytes([]byte(item))
+}
+
+func (cf *CuckooFilter) Contains(item string) bool {
+	return cf.ContainsBytes([]byte(item))
+}
+
+// Delete removes one copy of an item added earlier and reports whether it
+// found one. Deleting an item that was never added may remove another
+// item with the same fingerprint.
+func (cf *CuckooFilter) Delete(item string) bool {
+	return cf.DeleteBytes([]byte(item))
+}
+
+// AddBytes adds data to the filter, or returns ErrCuckooFull. An item can
+// be added more than once, taking a slot each time.
+func (cf *CuckooFilter) AddBytes(data []byte) error {
+	fp, i1, i2 := cf.locate(data)
+	if cf.insert(i1, fp) || cf.insert(i2, fp) {
+		cf.count++
+		return nil
+	}
+
+	// Both buckets are full: evict a random fingerprint to its other
+	// bucket, and so on, remembering each swap to undo them on failure.
+	type swap struct{ slot, fp uint64 }
+	path := make([]swap, 0, cf.maxKicks)
+	i := i1
+	if cf.rng.Intn(2) == 1 {
+		i = i2
+	}
+	for range cf.maxKicks {
+		slot := i*cf.bucketSize + uint64(cf.rng.Intn(int(cf.bucketSize)))
+		evicted := cf.slots.get(slot)
+		cf.slots.set(slot, fp)
+		path = append(path, swap{slot, evicted})
+
+		fp = evicted
+		i = cf.altIndex(i, fp)
+		if cf.insert(i, fp) {
+			cf.count++
+			return nil
+		}
+	}
+
+	for j := len(path) - 1; j >= 0; j-- {
+		cf.slots.set(path[j].slot, path[j].fp)
+	}
+	return ErrCuckooFull
+}
+
+// ContainsBytes reports whether data may have been added to the filter.
+func (cf *CuckooFilter) ContainsBytes(data []byte) bool {
+	fp, i1, i2 := cf.locate(data)
+	return cf.find(i1, fp) >= 0 || cf.find(i2, fp) >= 0
+}
+
+// DeleteBytes is Delete for a byte slice.
+func (cf *CuckooFilter) DeleteBytes(data []byte) bool {
+	fp, i1, i2 := cf.locate(data)
+	for _, i := range []uint64{i1, i2} {
+		if slot := cf.find(i, fp); slot >= 0 {
+			cf.slots.set(uint64(slot), 0)
+			cf.count--
+			return true
+		}
+	}
+	return false
+}
+
+// Clear removes every item.
+func (cf *CuckooFilter) Clear() {
+	clear(cf.slots.words)
+	cf.count = 0
+}
+
+// Count returns the number of items in the filter.
+func (cf *CuckooFilter) Count() int {
+	return cf.count
+}
+
+// Capacity returns the number of slots, the most items the filter can
+// ever hold.
+func (cf *CuckooFilter) Capacity() int {
+	return int(cf.buckets * cf.bucketSize)
+}
+
+// LoadFactor returns the fraction of slots in use.
+func (cf *CuckooFilter) LoadFactor() float64 {
+	return float64(cf.count) / float64(cf.Capacity())
+}
+
+// FingerprintBits returns the size of each stored fingerprint.
+func (cf *CuckooFilter) FingerprintBits() int {
+	return int(cf.slots.width)
+}
+
+// BucketSize returns the number of fingerprints per bucket.
+func (cf *CuckooFilter) BucketSize() int {
+	return int(cf.bucketSize)
+}
+
+// locate returns the fingerprint of data and its two candidate buckets.
+func (cf *CuckooFilter) locate(data []byte) (fp, i1, i2 uint64) {
+	h1, h2 := murmur3Sum128(data, cf.seed)
+	fp = h2 & cf.slots.max
+	if fp == 0 {
+		fp = 1
+	}
+	i1 = h1 & (cf.buckets - 1)
+	return fp, i1, cf.altIndex(i1, fp)
+}
+
+// altIndex returns the other bucket for fp in bucket i. With a power of
+// two bucket count it is its own inverse, so it can be computed from
+// either bucket without the original item.
+func (cf *CuckooFilter) altIndex(i, fp uint64) uint64 {
+	return (i ^ fmix64(fp)) & (cf.buckets - 1)
+}
+
+func (cf *CuckooFilter) insert(i, fp uint64) bool {
+	for slot := i * cf.bucketSize; slot < (i+1)*cf.bucketSize; slot++ {
+		if cf.slots.get(slot) == 0 {
+			cf.slots.set(slot, fp)
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the slot holding fp in bucket i, or -1.
+func (cf *CuckooFilter) find(i, fp uint64) int64 {
+	for slot := i * cf.bucketSize; slot < (i+1)*cf.bucketSize; slot++ {
+		if cf.slots.get(slot) == fp {
+			return int64(slot)
+		}
+	}
+	return -1
+}
@@ -0,0 +1,102 @@
+package bloomfilter_test
+
+import (
+	"fmt"
+	"testing"
+
+	bloomfilter "dsalgo/bloom-filter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCuckooAddContainsDelete(t *testing.T) {
+	const n = 10000
+	cf, err := bloomfilter.NewCuckooFilterWithOptions(bloomfilter.CuckooOptions{Capacity: n, Seed: 1})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, cf.Capacity(), n)
+	assert.Equal(t, bloomfilter.DefaultFingerprintBits, cf.FingerprintBits())
+	assert.Equal(t, bloomfilter.DefaultBucketSize, cf.BucketSize())
+
+	for i := range n {
+		require.NoError(t, cf.Add(fmt.Sprintf("item-%d", i)))
+	}
+	assert.Equal(t, n, cf.Count())
+	for i := range n {
+		require.True(t, cf.Contains(fmt.Sprintf("item-%d", i)))
+	}
+
+	for i := range n / 2 {
+		require.True(t, cf.Delete(fmt.Sprintf("item-%d", i)))
+	}
+	assert.Equal(t, n/2, cf.Count())
+	for i := n / 2; i < n; i++ {
+		require.True(t, cf.Contains(fmt.Sprintf("item-%d", i)), "deleting other items caused a false negative")
+	}
+	assert.False(t, cf.Delete("never-added"))
+
+	// Bounded by 2 * 4 / 2^16.
+	falsePositives := 0
+	for i := range 100000 {
+		if cf.Contains(fmt.Sprintf("other-%d", i)) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 20)
+
+	cf.Clear()
+	assert.Zero(t, cf.Count())
+	assert.False(t, cf.Contains(fmt.Sprintf("item-%d", n-1)))
+}
+
+func TestCuckooFull(t *testing.T) {
+	cf, err := bloomfilter.NewCuckooFilterWithOptions(bloomfilter.CuckooOptions{
+		Capacity:        64,
+		FingerprintBits: 8,
+		BucketSize:      2,
+		MaxKicks:        50,
+		Seed:            1,
+	})
+	require.NoError(t, err)
+
+	added := 0
+	for ; added < 10*cf.Capacity(); added++ {
+		err = cf.Add(fmt.Sprintf("item-%d", added))
+		if err != nil {
+			break
+		}
+	}
+	require.ErrorIs(t, err, bloomfilter.ErrCuckooFull)
+	assert.Equal(t, added, cf.Count())
+	assert.LessOrEqual(t, added, cf.Capacity())
+
+	// A failed Add must not lose anything that was already in the filter.
+	for i := range added {
+		require.True(t, cf.Contains(fmt.Sprintf("item-%d", i)))
+	}
+}
+
+func TestCuckooOptionsValidation(t *testing.T) {
+	for name, opts := range map[string]bloomfilter.CuckooOptions{
+		"zero capacity":        {Capacity: 0},
+		"odd fingerprint size": {Capacity: 10, FingerprintBits: 12},
+		"huge bucket":          {Capacity: 10, BucketSize: 9},
+		"negative kicks":       {Capacity: 10, MaxKicks: -1},
+	} {
+		_, err := bloomfilter.NewCuckooFilterWithOptions(opts)
+		assert.ErrorIs(t, err, bloomfilter.ErrInvalidOptions, name)
+	}
+	assert.Panics(t, func() { bloomfilter.NewCuckooFilter(0) })
+}
+
+func TestMembershipInterface(t *testing.T) {
+	cf := bloomfilter.NewCuckooFilter(100)
+	require.NoError(t, cf.Add("alpha"))
+	bf := bloomfilter.NewBloomFilter(0.01, 100)
+	bf.Add("alpha")
+
+	for _, filter := range []bloomfilter.Membership{bf, cf} {
+		assert.True(t, filter.Contains("alpha"))
+		assert.True(t, filter.ContainsBytes([]byte("alpha")))
+	}
+}
@@ -0,0 +1,225 @@
+package bloomfilter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// The binary encoding of a filter is, little endian throughout:
+//
+//	magic    [4]byte "BLMF"
+//	version  uint8
+//	scheme   uint8    HashScheme
+//	m        uint64   number of bits
+//	k        uint32   number of hash functions
+//	seeds    []uint32 k for HashMurmur3Seeded, 1 for HashMurmur3KM
+//	words    [(m+63)/64]uint64
+//	checksum uint32   CRC-32 (IEEE) of everything before it
+const (
+	encodingMagic   = "BLMF"
+	encodingVersion = 1
+)
+
+// wordChunk is how many words are encoded or decoded at a time, so that
+// neither a large filter nor a corrupt header needs one huge buffer.
+const wordChunk = 4096
+
+var (
+	ErrBadMagic           = errors.New("bloomfilter: not an encoded bloom filter")
+	ErrUnsupportedVersion = errors.New("bloomfilter: unsupported encoding version")
+	ErrChecksum           = errors.New("bloomfilter: checksum mismatch")
+)
+
+type header struct {
+	Magic   [4]byte
+	Version uint8
+	Scheme  HashScheme
+	M       uint64
+	K       uint32
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (bf *BloomFilter) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(4 + 2 + 8 + 4 + 4*len(bf.seeds) + 8*len(bf.bits) + 4)
+	if _, err := bf.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It replaces the
+// filter with the encoded one.
+func (bf *BloomFilter) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if _, err := bf.ReadFrom(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("bloomfilter: %d trailing bytes after encoded filter", r.Len())
+	}
+	return nil
+}
+
+// WriteTo implements io.WriterTo, writing the filter's binary encoding.
+func (bf *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	crc := crc32.NewIEEE()
+	out := io.MultiWriter(cw, crc)
+
+	h := header{
+		Version: encodingVersion,
+		Scheme:  bf.scheme,
+		M:       bf.m,
+		K:       uint32(bf.hashCount),
+	}
+	copy(h.Magic[:], encodingMagic)
+	if err := binary.Write(out, binary.LittleEndian, h); err != nil {
+		return cw.n, err
+	}
+	if err := binary.Write(out, binary.LittleEndian, bf.seeds); err != nil {
+		return cw.n, err
+	}
+
+	if err := writeWords(out, bf.bits); err != nil {
+		return cw.n, err
+	}
+
+	err := binary.Write(cw, binary.LittleEndian, crc.Sum32())
+	return cw.n, err
+}
+
+// ReadFrom implements io.ReaderFrom. It reads exactly one encoded filter
+// from r, leaving anything after it unread, and replaces the filter with
+// it. On error the filter is left unchanged.
+func (bf *BloomFilter) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	crc := crc32.NewIEEE()
+	in := io.TeeReader(cr, crc)
+
+	var h header
+	if err := binary.Read(in, binary.LittleEndian, &h); err != nil {
+		return cr.n, unexpectedEOF(err)
+	}
+	if string(h.Magic[:]) != encodingMagic {
+		return cr.n, ErrBadMagic
+	}
+	if h.Version != encodingVersion {
+		return cr.n, fmt.Errorf("%w %d", ErrUnsupportedVersion, h.Version)
+	}
+	if !h.Scheme.valid() {
+		return cr.n, fmt.Errorf("bloomfilter: unknown hash scheme %d", h.Scheme)
+	}
+	if h.M == 0 || h.M > maxBits || h.K == 0 {
+		return cr.n, fmt.Errorf("bloomfilter: invalid filter with m=%d, k=%d", h.M, h.K)
+	}
+
+	seedCount := h.Scheme.seedCount(int(h.K))
+	seeds := make([]uint32, 0, min(seedCount, wordChunk))
+	for len(seeds) < seedCount {
+		chunk := make([]uint32, min(seedCount-len(seeds), wordChunk))
+		if err := binary.Read(in, binary.LittleEndian, chunk); err != nil {
+			return cr.n, unexpectedEOF(err)
+		}
+		seeds = append(seeds, chunk...)
+	}
+
+	bits, err := readWords(in, (h.M+63)/64)
+	if err != nil {
+		return cr.n, err
+	}
+	if err := readChecksum(cr, crc.Sum32()); err != nil {
+		return cr.n, err
+	}
+	if tail := h.M % 64; tail != 0 && bits[len(bits)-1]>>tail != 0 {
+		return cr.n, fmt.Errorf("bloomfilter: invalid filter with bits set beyond m=%d", h.M)
+	}
+
+	*bf = BloomFilter{
+		bits:      bits,
+		m:         h.M,
+		hashCount: int64(h.K),
+		scheme:    h.Scheme,
+		seeds:     seeds,
+	}
+	return cr.n, nil
+}
+
+// writeWords writes words little endian, a chunk at a time.
+func writeWords(w io.Writer, words []uint64) error {
+	buf := make([]byte, 0, 8*wordChunk)
+	for start := 0; start < len(words); start += wordChunk {
+		buf = buf[:0]
+		for _, word := range words[start:min(start+wordChunk, len(words))] {
+			buf = binary.LittleEndian.AppendUint64(buf, word)
+		}
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readWords reads count little endian words, growing the result as data
+// arrives rather than trusting count up front.
+func readWords(r io.Reader, count uint64) ([]uint64, error) {
+	words := make([]uint64, 0, min(count, wordChunk))
+	buf := make([]byte, 8*wordChunk)
+	for uint64(len(words)) < count {
+		chunk := buf[:8*min(count-uint64(len(words)), wordChunk)]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		for i := 0; i < len(chunk); i += 8 {
+			words = append(words, binary.LittleEndian.Uint64(chunk[i:]))
+		}
+	}
+	return words, nil
+}
+
+// readChecksum reads the trailing checksum and compares it with sum.
+func readChecksum(r io.Reader, sum uint32) error {
+	var checksum uint32
+	if err := binary.Read(r, binary.LittleEndian, &checksum); err != nil {
+		return unexpectedEOF(err)
+	}
+	if checksum != sum {
+		return ErrChecksum
+	}
+	return nil
+}
+
+// unexpectedEOF reports running out of input part way through a filter as
+// io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
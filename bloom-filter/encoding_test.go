@@ -0,0 +1,131 @@
+package bloomfilter_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"math"
+	"testing"
+
+	bloomfilter "dsalgo/bloom-filter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	bf := bloomfilter.NewBloomFilter(0.01, 1000)
+	for _, word := range []string{"alpha", "bravo", "charlie"} {
+		bf.Add(word)
+	}
+
+	data, err := bf.MarshalBinary()
+	require.NoError(t, err)
+
+	var loaded bloomfilter.BloomFilter
+	require.NoError(t, loaded.UnmarshalBinary(data))
+
+	assert.Equal(t, bf.Size(), loaded.Size())
+	assert.Equal(t, bf.HashCount(), loaded.HashCount())
+	assert.Equal(t, bf.Seeds(), loaded.Seeds())
+	assert.Equal(t, bf.Words(), loaded.Words())
+	assert.True(t, loaded.Contains("bravo"))
+
+	// The reloaded filter hashes like the original.
+	bf.Add("delta")
+	loaded.Add("delta")
+	assert.Equal(t, bf.Words(), loaded.Words())
+}
+
+func TestWriteToReadFrom(t *testing.T) {
+	a := bloomfilter.NewBloomFilterWithSeed(0.01, 100000, 1)
+	b := bloomfilter.NewBloomFilterWithSeed(0.001, 10, 2)
+	a.Add("a")
+	b.Add("b")
+
+	var buf bytes.Buffer
+	na, err := a.WriteTo(&buf)
+	require.NoError(t, err)
+	nb, err := b.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), na+nb)
+
+	// Filters are self delimiting, so they can be read back to back.
+	var loadedA, loadedB bloomfilter.BloomFilter
+	n, err := loadedA.ReadFrom(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, na, n)
+	n, err = loadedB.ReadFrom(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, nb, n)
+
+	assert.Equal(t, a.Words(), loadedA.Words())
+	assert.Equal(t, b.Words(), loadedB.Words())
+	assert.True(t, loadedB.Contains("b"))
+}
+
+func TestUnmarshalBinaryRejectsBadInput(t *testing.T) {
+	bf := bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 1)
+	bf.Add("alpha")
+	data, err := bf.MarshalBinary()
+	require.NoError(t, err)
+
+	var loaded bloomfilter.BloomFilter
+
+	corrupt := bytes.Clone(data)
+	corrupt[len(corrupt)/2] ^= 0x01
+	assert.ErrorIs(t, loaded.UnmarshalBinary(corrupt), bloomfilter.ErrChecksum)
+
+	badMagic := bytes.Clone(data)
+	badMagic[0] = 'X'
+	assert.ErrorIs(t, loaded.UnmarshalBinary(badMagic), bloomfilter.ErrBadMagic)
+
+	badVersion := bytes.Clone(data)
+	badVersion[4] = 99
+	assert.ErrorIs(t, loaded.UnmarshalBinary(badVersion), bloomfilter.ErrUnsupportedVersion)
+
+	assert.ErrorIs(t, loaded.UnmarshalBinary(data[:len(data)-1]), io.ErrUnexpectedEOF)
+	assert.Error(t, loaded.UnmarshalBinary(append(bytes.Clone(data), 0)))
+}
+
+func TestUnmarshalBinaryRejectsOversizedFilter(t *testing.T) {
+	// A well formed encoding whose m would wrap the word count to zero.
+	data := []byte("BLMF")
+	data = append(data, 1, byte(bloomfilter.HashMurmur3Seeded))
+	data = binary.LittleEndian.AppendUint64(data, math.MaxUint64)
+	data = binary.LittleEndian.AppendUint32(data, 1)
+	data = binary.LittleEndian.AppendUint32(data, 42)
+	data = binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+
+	var loaded bloomfilter.BloomFilter
+	assert.Error(t, loaded.UnmarshalBinary(data))
+}
+
+func TestUnmarshalBinaryRejectsUnallocatableFilter(t *testing.T) {
+	// m needs 1<<45 words, one more than make will allocate.
+	data := []byte("BLMF")
+	data = append(data, 1, byte(bloomfilter.HashMurmur3Seeded))
+	data = binary.LittleEndian.AppendUint64(data, 1<<51)
+	data = binary.LittleEndian.AppendUint32(data, 1)
+	data = binary.LittleEndian.AppendUint32(data, 42)
+
+	var loaded bloomfilter.BloomFilter
+	assert.ErrorContains(t, loaded.UnmarshalBinary(data), "invalid filter")
+}
+
+func TestUnmarshalBinaryRejectsBitsBeyondM(t *testing.T) {
+	bf, err := bloomfilter.NewBloomFilterWithSize(100, 3, 0, 1)
+	require.NoError(t, err)
+	bf.Add("alpha")
+	data, err := bf.MarshalBinary()
+	require.NoError(t, err)
+
+	// Set bit 127, the top bit of the last word, and fix up the checksum.
+	body := data[:len(data)-4]
+	body[len(body)-1] |= 0x80
+	data = binary.LittleEndian.AppendUint32(body, crc32.ChecksumIEEE(body))
+
+	var loaded bloomfilter.BloomFilter
+	assert.ErrorContains(t, loaded.UnmarshalBinary(data), "beyond m=100")
+}
@@ -0,0 +1,368 @@
+package bloomfilter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"math/bits"
+	"math/rand"
+	"slices"
+)
+
+// ErrFuseConstruction is returned when no filter could be built from a key
+// set within the retry limit. It is vanishingly unlikely for distinct keys.
+var ErrFuseConstruction = errors.New("bloomfilter: could not build binary fuse filter")
+
+const (
+	DefaultFuseFingerprintBits = 8
+
+	// fuseMaxAttempts bounds how many seeds construction tries.
+	fuseMaxAttempts = 100
+)
+
+// BinaryFuseFilter is an immutable binary fuse filter (Graf and Lemire,
+// "Binary Fuse Filters: Fast and Smaller Than Xor Filters") built once from
+// a complete key set. Each key maps to three fingerprint slots in adjacent
+// segments whose XOR equals the key's fingerprint. It takes about 1.125
+// fingerprints per key, with a false positive rate of 2^-FingerprintBits.
+type BinaryFuseFilter struct {
+	fingerprints  counters
+	keySeed       uint32 // seeds the murmur3 hash of each key
+	mixSeed       uint64 // the seed construction succeeded with
+	segmentLength uint32 // a power of two
+	segmentCount  uint32
+	count         uint64
+}
+
+// FuseOptions configures BuildBinaryFuseFilter.
+type FuseOptions struct {
+	// FingerprintBits is 8, 16 or 32; zero means
+	// DefaultFuseFingerprintBits.
+	FingerprintBits int
+	// Seed seeds the key hash and the construction retries, so equal key
+	// sets and options build equal filters.
+	Seed uint64
+}
+
+// BuildBinaryFuseFilter builds a filter containing exactly keys. Duplicate
+// keys are allowed. It returns an error wrapping ErrInvalidOptions for bad
+// options, or ErrFuseConstruction.
+func BuildBinaryFuseFilter(keys [][]byte, opts FuseOptions) (*BinaryFuseFilter, error) {
+	if opts.FingerprintBits == 0 {
+		opts.FingerprintBits = DefaultFuseFingerprintBits
+	}
+	switch opts.FingerprintBits {
+	case 8, 16, 32:
+	default:
+		return nil, fmt.Errorf("%w: unsupported fingerprint size %d", ErrInvalidOptions, opts.FingerprintBits)
+	}
+
+	keySeed := deriveSeeds(opts.Seed, 1)[0]
+	hashes := make([]uint64, len(keys))
+	for i, key := range keys {
+		hashes[i], _ = murmur3Sum128(key, keySeed)
+	}
+	slices.Sort(hashes)
+	hashes = slices.Compact(hashes)
+	// Slot numbers, about 1.125 per key, must fit in a uint32.
+	if len(hashes) > math.MaxUint32/2 {
+		return nil, fmt.Errorf("%w: %d keys is too many", ErrInvalidOptions, len(hashes))
+	}
+
+	f := newBinaryFuseFilter(uint32(len(hashes)), uint(opts.FingerprintBits))
+	f.keySeed = keySeed
+	rng := rand.New(rand.NewSource(int64(opts.Seed)))
+	for range fuseMaxAttempts {
+		f.mixSeed = rng.Uint64()
+		if f.populate(hashes) {
+			return f, nil
+		}
+	}
+	return nil, ErrFuseConstruction
+}
+
+// BuildBinaryFuseFilterFromStrings is BuildBinaryFuseFilter for string
+// keys.
+func BuildBinaryFuseFilterFromStrings(keys []string, opts FuseOptions) (*BinaryFuseFilter, error) {
+	byteKeys := make([][]byte, len(keys))
+	for i, key := range keys {
+		byteKeys[i] = []byte(key)
+	}
+	return BuildBinaryFuseFilter(byteKeys, opts)
+}
+
+// newBinaryFuseFilter sizes a filter for n distinct keys with 3-wise
+// segments, using the parameters from the paper's reference code.
+func newBinaryFuseFilter(n uint32, fingerprintBits uint) *BinaryFuseFilter {
+	segmentBits := 2
+	if n > 1 {
+		segmentBits = int(math.Floor(math.Log(float64(n))/math.Log(3.33) + 2.25))
+		segmentBits = min(max(segmentBits, 2), 18)
+	}
+	segmentLength := uint32(1) << segmentBits
+
+	capacity := uint64(0)
+	if n > 1 {
+		sizeFactor := math.Max(1.125, 0.875+0.25*math.Log(1000000)/math.Log(float64(n)))
+		capacity = uint64(math.Round(float64(n) * sizeFactor))
+	}
+	segmentCount := uint32(max((capacity+uint64(segmentLength)-1)/uint64(segmentLength), 3) - 2)
+
+	return &BinaryFuseFilter{
+		fingerprints:  newCounters(uint64(segmentCount+2)*uint64(segmentLength), fingerprintBits),
+		segmentLength: segmentLength,
+		segmentCount:  segmentCount,
+		count:         uint64(n),
+	}
+}
+
+// populate tries to fill the fingerprints for the given distinct key hashes
+// with the current mixSeed. It reports false if the keys' slots could not
+// be peeled, in which case another seed must be tried.
+func (f *BinaryFuseFilter) populate(keyHashes []uint64) bool {
+	size := f.size()
+	counts := make([]uint32, size)
+	xors := make([]uint64, size)
+	for _, kh := range keyHashes {
+		hash := f.mix(kh)
+		for _, slot := range f.slots(hash) {
+			counts[slot]++
+			xors[slot] ^= hash
+		}
+	}
+
+	// Peel: a slot used by a single key can be solved last, after the
+	// key's other two slots, so repeatedly take such keys off the graph.
+	type peeled struct {
+		hash uint64
+		slot uint32
+	}
+	stack := make([]peeled, 0, len(keyHashes))
+	queue := make([]uint32, 0, size)
+	for slot := range uint32(size) {
+		if counts[slot] == 1 {
+			queue = append(queue, slot)
+		}
+	}
+	for len(queue) > 0 {
+		slot := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if counts[slot] != 1 {
+			continue
+		}
+		hash := xors[slot]
+		stack = append(stack, peeled{hash, slot})
+		for _, other := range f.slots(hash) {
+			counts[other]--
+			xors[other] ^= hash
+			if counts[other] == 1 {
+				queue = append(queue, other)
+			}
+		}
+	}
+	if len(stack) != len(keyHashes) {
+		return false
+	}
+
+	clear(f.fingerprints.words)
+	for i := len(stack) - 1; i >= 0; i-- {
+		hash, slot := stack[i].hash, stack[i].slot
+		fp := f.fingerprint(hash)
+		for _, other := range f.slots(hash) {
+			if other != slot {
+				fp ^= f.fingerprints.get(uint64(other))
+			}
+		}
+		f.fingerprints.set(uint64(slot), fp)
+	}
+	return true
+}
+
+func (f *BinaryFuseFilter) Contains(item string) bool {
+	return f.ContainsBytes([]byte(item))
+}
+
+// ContainsBytes reports whether data may be one of the keys the filter was
+// built from.
+func (f *BinaryFuseFilter) ContainsBytes(data []byte) bool {
+	kh, _ := murmur3Sum128(data, f.keySeed)
+	hash := f.mix(kh)
+	fp := f.fingerprint(hash)
+	for _, slot := range f.slots(hash) {
+		fp ^= f.fingerprints.get(uint64(slot))
+	}
+	return fp == 0
+}
+
+// Count returns the number of distinct keys the filter was built from.
+func (f *BinaryFuseFilter) Count() int {
+	return int(f.count)
+}
+
+// FingerprintBits returns the size of each fingerprint.
+func (f *BinaryFuseFilter) FingerprintBits() int {
+	return int(f.fingerprints.width)
+}
+
+// SizeBits returns the size of the fingerprint array in bits.
+func (f *BinaryFuseFilter) SizeBits() int {
+	return int(f.size()) * int(f.fingerprints.width)
+}
+
+// size returns the number of fingerprint slots.
+func (f *BinaryFuseFilter) size() uint64 {
+	return (uint64(f.segmentCount) + 2) * uint64(f.segmentLength)
+}
+
+// FalsePositiveRate returns the chance that ContainsBytes reports a key
+// the filter was not built from.
+func (f *BinaryFuseFilter) FalsePositiveRate() float64 {
+	return math.Ldexp(1, -int(f.fingerprints.width))
+}
+
+func (f *BinaryFuseFilter) mix(keyHash uint64) uint64 {
+	return fmix64(keyHash + f.mixSeed)
+}
+
+func (f *BinaryFuseFilter) fingerprint(hash uint64) uint64 {
+	return (hash ^ hash>>32) & f.fingerprints.max
+}
+
+// slots returns the three slots of hash: one in each of three consecutive
+// segments, the first chosen by the high bits of the hash.
+func (f *BinaryFuseFilter) slots(hash uint64) [3]uint32 {
+	hi, _ := bits.Mul64(hash, uint64(f.segmentCount)*uint64(f.segmentLength))
+	mask := f.segmentLength - 1
+	h0 := uint32(hi)
+	h1 := (h0 + f.segmentLength) ^ uint32(hash>>18)&mask
+	h2 := (h0 + 2*f.segmentLength) ^ uint32(hash)&mask
+	return [3]uint32{h0, h1, h2}
+}
+
+// The binary encoding of a BinaryFuseFilter is, little endian throughout:
+//
+//	magic          [4]byte "BFUS"
+//	version        uint8
+//	fpBits         uint8
+//	keySeed        uint32
+//	mixSeed        uint64
+//	segmentLength  uint32
+//	segmentCount   uint32
+//	count          uint64
+//	words          fingerprints packed as by counters
+//	checksum       uint32 CRC-32 (IEEE) of everything before it
+const (
+	fuseMagic   = "BFUS"
+	fuseVersion = 1
+)
+
+type fuseHeader struct {
+	Magic         [4]byte
+	Version       uint8
+	FPBits        uint8
+	KeySeed       uint32
+	MixSeed       uint64
+	SegmentLength uint32
+	SegmentCount  uint32
+	Count         uint64
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (f *BinaryFuseFilter) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It replaces the
+// filter with the encoded one.
+func (f *BinaryFuseFilter) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if _, err := f.ReadFrom(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("bloomfilter: %d trailing bytes after encoded filter", r.Len())
+	}
+	return nil
+}
+
+// WriteTo implements io.WriterTo, writing the filter's binary encoding.
+func (f *BinaryFuseFilter) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	crc := crc32.NewIEEE()
+	out := io.MultiWriter(cw, crc)
+
+	h := fuseHeader{
+		Version:       fuseVersion,
+		FPBits:        uint8(f.fingerprints.width),
+		KeySeed:       f.keySeed,
+		MixSeed:       f.mixSeed,
+		SegmentLength: f.segmentLength,
+		SegmentCount:  f.segmentCount,
+		Count:         f.count,
+	}
+	copy(h.Magic[:], fuseMagic)
+	if err := binary.Write(out, binary.LittleEndian, h); err != nil {
+		return cw.n, err
+	}
+	if err := writeWords(out, f.fingerprints.words); err != nil {
+		return cw.n, err
+	}
+
+	err := binary.Write(cw, binary.LittleEndian, crc.Sum32())
+	return cw.n, err
+}
+
+// ReadFrom implements io.ReaderFrom. Like BloomFilter.ReadFrom it reads
+// exactly one encoded filter and leaves the filter unchanged on error.
+func (f *BinaryFuseFilter) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	crc := crc32.NewIEEE()
+	in := io.TeeReader(cr, crc)
+
+	var h fuseHeader
+	if err := binary.Read(in, binary.LittleEndian, &h); err != nil {
+		return cr.n, unexpectedEOF(err)
+	}
+	if string(h.Magic[:]) != fuseMagic {
+		return cr.n, ErrBadMagic
+	}
+	if h.Version != fuseVersion {
+		return cr.n, fmt.Errorf("%w %d", ErrUnsupportedVersion, h.Version)
+	}
+	switch h.FPBits {
+	case 8, 16, 32:
+	default:
+		return cr.n, fmt.Errorf("bloomfilter: invalid fingerprint size %d", h.FPBits)
+	}
+	if h.SegmentLength == 0 || h.SegmentLength&(h.SegmentLength-1) != 0 || h.SegmentCount == 0 {
+		return cr.n, fmt.Errorf("bloomfilter: invalid segments %d x %d", h.SegmentCount, h.SegmentLength)
+	}
+
+	loaded := &BinaryFuseFilter{
+		fingerprints:  newCounters(0, uint(h.FPBits)),
+		keySeed:       h.KeySeed,
+		mixSeed:       h.MixSeed,
+		segmentLength: h.SegmentLength,
+		segmentCount:  h.SegmentCount,
+		count:         h.Count,
+	}
+	words, err := readWords(in, loaded.fingerprints.wordsFor(loaded.size()))
+	if err != nil {
+		return cr.n, err
+	}
+	loaded.fingerprints.words = words
+	if err := readChecksum(cr, crc.Sum32()); err != nil {
+		return cr.n, err
+	}
+
+	*f = *loaded
+	return cr.n, nil
+}
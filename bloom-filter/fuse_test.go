@@ -0,0 +1,101 @@
+package bloomfilter_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	bloomfilter "dsalgo/bloom-filter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fuseKeys(prefix string, n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s-%d", prefix, i)
+	}
+	return keys
+}
+
+func TestBinaryFuseFilter(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 10, 1000, 100000} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			keys := fuseKeys("key", n)
+			f, err := bloomfilter.BuildBinaryFuseFilterFromStrings(keys, bloomfilter.FuseOptions{Seed: 1})
+			require.NoError(t, err)
+			assert.Equal(t, n, f.Count())
+			for _, key := range keys {
+				require.True(t, f.Contains(key), key)
+			}
+		})
+	}
+}
+
+func TestBinaryFuseFilterFalsePositives(t *testing.T) {
+	const n = 100000
+	f, err := bloomfilter.BuildBinaryFuseFilterFromStrings(fuseKeys("key", n), bloomfilter.FuseOptions{Seed: 2})
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0/256, f.FalsePositiveRate(), 1e-12)
+
+	falsePositives := 0
+	for _, key := range fuseKeys("other", n) {
+		if f.Contains(key) {
+			falsePositives++
+		}
+	}
+	assert.InDelta(t, f.FalsePositiveRate(), float64(falsePositives)/n, 0.001)
+
+	// Smaller than a Bloom filter at the same rate.
+	bf := bloomfilter.NewBloomFilter(f.FalsePositiveRate(), n)
+	assert.Less(t, float64(f.SizeBits()), 0.85*float64(bf.Size()))
+}
+
+func TestBinaryFuseFilterDuplicatesAndOptions(t *testing.T) {
+	keys := append(fuseKeys("key", 500), fuseKeys("key", 500)...)
+	f, err := bloomfilter.BuildBinaryFuseFilterFromStrings(keys, bloomfilter.FuseOptions{FingerprintBits: 16, Seed: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 500, f.Count())
+	assert.Equal(t, 16, f.FingerprintBits())
+	assert.True(t, f.Contains("key-499"))
+
+	_, err = bloomfilter.BuildBinaryFuseFilterFromStrings(keys, bloomfilter.FuseOptions{FingerprintBits: 12})
+	assert.ErrorIs(t, err, bloomfilter.ErrInvalidOptions)
+}
+
+func TestBinaryFuseFilterEncoding(t *testing.T) {
+	keys := fuseKeys("key", 1000)
+	f, err := bloomfilter.BuildBinaryFuseFilterFromStrings(keys, bloomfilter.FuseOptions{FingerprintBits: 16, Seed: 4})
+	require.NoError(t, err)
+
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+	var loaded bloomfilter.BinaryFuseFilter
+	require.NoError(t, loaded.UnmarshalBinary(data))
+	assert.Equal(t, f.Count(), loaded.Count())
+	assert.Equal(t, f.SizeBits(), loaded.SizeBits())
+	for _, key := range keys {
+		require.True(t, loaded.Contains(key))
+	}
+	for _, key := range fuseKeys("other", 1000) {
+		assert.Equal(t, f.Contains(key), loaded.Contains(key))
+	}
+
+	var buf bytes.Buffer
+	n, err := f.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), n)
+
+	corrupt := bytes.Clone(data)
+	corrupt[len(corrupt)/2] ^= 0x01
+	assert.ErrorIs(t, loaded.UnmarshalBinary(corrupt), bloomfilter.ErrChecksum)
+
+	// A Bloom filter encoding is not a fuse filter.
+	bfData, err := bloomfilter.NewBloomFilter(0.01, 10).MarshalBinary()
+	require.NoError(t, err)
+	assert.ErrorIs(t, loaded.UnmarshalBinary(bfData), bloomfilter.ErrBadMagic)
+
+	var membership bloomfilter.Membership = &loaded
+	assert.True(t, membership.ContainsBytes([]byte("key-1")))
+}
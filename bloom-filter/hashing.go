@@ -0,0 +1,72 @@
+package bloomfilter
+
+import "fmt"
+
+// HashScheme identifies how a filter maps items to bit positions. It is
+// part of a filter's identity: filters only agree on an item's bits if
+// they share the scheme, size, hash count and seeds.
+type HashScheme uint8
+
+const (
+	// HashMurmur3Seeded runs one 64-bit murmur3 per hash function, each
+	// with its own seed. This is what NewBloomFilter uses.
+	HashMurmur3Seeded HashScheme = 1
+	// HashMurmur3KM derives all k indexes from a single seeded 128-bit
+	// murmur3 pass, as h1 + i*h2 (Kirsch and Mitzenmacher, "Less Hashing,
+	// Same Performance"). It needs one seed whatever k is.
+	HashMurmur3KM HashScheme = 2
+)
+
+func (s HashScheme) String() string {
+	switch s {
+	case HashMurmur3Seeded:
+		return "murmur3-seeded"
+	case HashMurmur3KM:
+		return "murmur3-km"
+	default:
+		return fmt.Sprintf("HashScheme(%d)", uint8(s))
+	}
+}
+
+// ParseHashScheme returns the scheme whose String is name; an empty name
+// means HashMurmur3Seeded.
+func ParseHashScheme(name string) (HashScheme, error) {
+	switch name {
+	case "", HashMurmur3Seeded.String():
+		return HashMurmur3Seeded, nil
+	case HashMurmur3KM.String():
+		return HashMurmur3KM, nil
+	default:
+		return 0, fmt.Errorf("unknown hash scheme %q", name)
+	}
+}
+
+func (s HashScheme) valid() bool {
+	return s == HashMurmur3Seeded || s == HashMurmur3KM
+}
+
+// seedCount returns how many seeds the scheme needs for k hash functions.
+func (s HashScheme) seedCount(k int) int {
+	if s == HashMurmur3KM {
+		return 1
+	}
+	return k
+}
+
+// indexes appends the k bit indexes of data to dst. It keeps no state
+// between calls, so it is safe for concurrent use.
+func (s HashScheme) indexes(dst []uint64, seeds []uint32, k int, m uint64, data []byte) []uint64 {
+	if s == HashMurmur3KM {
+		h1, h2 := murmur3Sum128(data, seeds[0])
+		for i := range uint64(k) {
+			dst = append(dst, (h1+i*h2)%m)
+		}
+		return dst
+	}
+
+	for _, seed := range seeds {
+		h1, _ := murmur3Sum128(data, seed)
+		dst = append(dst, h1%m)
+	}
+	return dst
+}
@@ -0,0 +1,92 @@
+package bloomfilter_test
+
+import (
+	"fmt"
+	"testing"
+
+	bloomfilter "dsalgo/bloom-filter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKirschMitzenmacher(t *testing.T) {
+	const n = 10000
+	bf := bloomfilter.NewBloomFilterWithScheme(0.01, n, bloomfilter.HashMurmur3KM, 1)
+	assert.Equal(t, bloomfilter.HashMurmur3KM, bf.HashScheme())
+	assert.Len(t, bf.Seeds(), 1)
+	assert.Equal(t, int64(7), bf.HashCount())
+
+	for i := range n {
+		bf.Add(fmt.Sprintf("item-%d", i))
+	}
+	for i := range n {
+		require.True(t, bf.Contains(fmt.Sprintf("item-%d", i)))
+	}
+
+	falsePositives := 0
+	for i := range n {
+		if bf.Contains(fmt.Sprintf("other-%d", i)) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, float64(falsePositives)/n, 0.02)
+
+	seeded := bloomfilter.NewBloomFilterWithScheme(0.01, n, bloomfilter.HashMurmur3Seeded, 1)
+	seeded.Add("item-0")
+	km := bloomfilter.NewBloomFilterWithScheme(0.01, n, bloomfilter.HashMurmur3KM, 1)
+	km.Add("item-0")
+	assert.NotEqual(t, seeded.Words(), km.Words())
+}
+
+func TestHashSchemeIsEncoded(t *testing.T) {
+	bf := bloomfilter.NewBloomFilterWithScheme(0.01, 1000, bloomfilter.HashMurmur3KM, 1)
+	bf.Add("alpha")
+
+	data, err := bf.MarshalBinary()
+	require.NoError(t, err)
+	var loaded bloomfilter.BloomFilter
+	require.NoError(t, loaded.UnmarshalBinary(data))
+	assert.Equal(t, bloomfilter.HashMurmur3KM, loaded.HashScheme())
+	assert.Equal(t, bf.Seeds(), loaded.Seeds())
+	assert.Equal(t, bf.HashCount(), loaded.HashCount())
+
+	bf.Add("bravo")
+	loaded.Add("bravo")
+	assert.Equal(t, bf.Words(), loaded.Words())
+	assert.Equal(t, bf.Words(), bf.Concurrent().Snapshot().Words())
+
+	data[5] = 9
+	assert.Error(t, loaded.UnmarshalBinary(data))
+}
+
+func BenchmarkAddByScheme(b *testing.B) {
+	for _, scheme := range []bloomfilter.HashScheme{bloomfilter.HashMurmur3Seeded, bloomfilter.HashMurmur3KM} {
+		b.Run(scheme.String(), func(b *testing.B) {
+			bf := bloomfilter.NewBloomFilterWithScheme(0.001, 1000000, scheme, 1)
+			words := make([]string, 1024)
+			for i := range words {
+				words[i] = randomWord(10)
+			}
+			b.ResetTimer()
+			for i := range b.N {
+				bf.Add(words[i%len(words)])
+			}
+		})
+	}
+}
+
+func TestParseHashScheme(t *testing.T) {
+	for _, scheme := range []bloomfilter.HashScheme{bloomfilter.HashMurmur3Seeded, bloomfilter.HashMurmur3KM} {
+		parsed, err := bloomfilter.ParseHashScheme(scheme.String())
+		require.NoError(t, err)
+		assert.Equal(t, scheme, parsed)
+	}
+
+	parsed, err := bloomfilter.ParseHashScheme("")
+	require.NoError(t, err)
+	assert.Equal(t, bloomfilter.HashMurmur3Seeded, parsed)
+
+	_, err = bloomfilter.ParseHashScheme("sha1")
+	assert.Error(t, err)
+}
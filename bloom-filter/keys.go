@@ -0,0 +1,69 @@
+package bloomfilter
+
+import "encoding/binary"
+
+// AddUint64 adds key to the filter. It sets the same bits as AddBytes on
+// the key's 8 byte little endian encoding, without allocating.
+func (bf *BloomFilter) AddUint64(key uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], key)
+	bf.AddBytes(buf[:])
+}
+
+// ContainsUint64 reports whether key may have been added with AddUint64.
+func (bf *BloomFilter) ContainsUint64(key uint64) bool {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], key)
+	return bf.ContainsBytes(buf[:])
+}
+
+// AddUint64 adds key to the filter, as BloomFilter.AddUint64 does.
+func (bf *ConcurrentBloomFilter) AddUint64(key uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], key)
+	bf.AddBytes(buf[:])
+}
+
+// ContainsUint64 reports whether key may have been added with AddUint64.
+func (bf *ConcurrentBloomFilter) ContainsUint64(key uint64) bool {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], key)
+	return bf.ContainsBytes(buf[:])
+}
+
+// Encoder appends the byte encoding of a key to dst and returns the
+// extended slice. Keys with equal encodings are the same key to a filter.
+type Encoder[T any] func(dst []byte, key T) []byte
+
+// Filter is a BloomFilter over keys of type T, turned into bytes by a user
+// supplied Encoder. A key sets the same bits as AddBytes on its encoding,
+// so a Filter and code working on raw bytes can share one BloomFilter.
+//
+// Keys are encoded into a buffer kept by the Filter, so once it has grown
+// to fit, Add and Contains do not allocate. That buffer makes a Filter
+// unsafe for concurrent use, even by Contains alone.
+type Filter[T any] struct {
+	bf     *BloomFilter
+	encode Encoder[T]
+	buf    []byte
+}
+
+// NewFilter wraps bf to take keys of type T.
+func NewFilter[T any](bf *BloomFilter, encode Encoder[T]) *Filter[T] {
+	return &Filter[T]{bf: bf, encode: encode}
+}
+
+func (f *Filter[T]) Add(key T) {
+	f.buf = f.encode(f.buf[:0], key)
+	f.bf.AddBytes(f.buf)
+}
+
+func (f *Filter[T]) Contains(key T) bool {
+	f.buf = f.encode(f.buf[:0], key)
+	return f.bf.ContainsBytes(f.buf)
+}
+
+// BloomFilter returns the underlying filter.
+func (f *Filter[T]) BloomFilter() *BloomFilter {
+	return f.bf
+}
@@ -0,0 +1,105 @@
+package bloomfilter_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	bloomfilter "dsalgo/bloom-filter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userID struct {
+	shard uint16
+	id    uint64
+}
+
+func encodeUserID(dst []byte, u userID) []byte {
+	dst = binary.BigEndian.AppendUint16(dst, u.shard)
+	return binary.BigEndian.AppendUint64(dst, u.id)
+}
+
+func TestKeyAPIsShareBitPositions(t *testing.T) {
+	for _, scheme := range []bloomfilter.HashScheme{bloomfilter.HashMurmur3Seeded, bloomfilter.HashMurmur3KM} {
+		t.Run(scheme.String(), func(t *testing.T) {
+			fromString := bloomfilter.NewBloomFilterWithScheme(0.01, 1000, scheme, 1)
+			fromBytes := bloomfilter.NewBloomFilterWithScheme(0.01, 1000, scheme, 1)
+			fromString.Add("alpha")
+			fromBytes.AddBytes([]byte("alpha"))
+			assert.Equal(t, fromString.Words(), fromBytes.Words())
+			assert.True(t, fromString.ContainsBytes([]byte("alpha")))
+
+			fromUint64 := bloomfilter.NewBloomFilterWithScheme(0.01, 1000, scheme, 1)
+			fromUint64Bytes := bloomfilter.NewBloomFilterWithScheme(0.01, 1000, scheme, 1)
+			fromUint64.AddUint64(42)
+			fromUint64Bytes.AddBytes(binary.LittleEndian.AppendUint64(nil, 42))
+			assert.Equal(t, fromUint64.Words(), fromUint64Bytes.Words())
+			assert.True(t, fromUint64.ContainsUint64(42))
+			assert.False(t, fromUint64.ContainsUint64(43))
+
+			generic := bloomfilter.NewFilter(bloomfilter.NewBloomFilterWithScheme(0.01, 1000, scheme, 1), encodeUserID)
+			fromEncoded := bloomfilter.NewBloomFilterWithScheme(0.01, 1000, scheme, 1)
+			generic.Add(userID{shard: 3, id: 7})
+			fromEncoded.AddBytes(encodeUserID(nil, userID{shard: 3, id: 7}))
+			assert.Equal(t, fromEncoded.Words(), generic.BloomFilter().Words())
+			assert.True(t, generic.Contains(userID{shard: 3, id: 7}))
+			assert.False(t, generic.Contains(userID{shard: 7, id: 3}))
+		})
+	}
+
+	cbf := bloomfilter.NewConcurrentBloomFilterWithSeed(0.01, 1000, 1)
+	bf := bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 1)
+	cbf.AddUint64(9)
+	bf.AddUint64(9)
+	assert.Equal(t, bf.Words(), cbf.Snapshot().Words())
+	assert.True(t, cbf.ContainsUint64(9))
+	assert.True(t, cbf.ContainsBytes(binary.LittleEndian.AppendUint64(nil, 9)))
+}
+
+func TestByteKeysOnCountingAndScalable(t *testing.T) {
+	fromString := bloomfilter.NewCountingBloomFilterWithCounterBits(0.01, 1000, 4, 1)
+	fromBytes := bloomfilter.NewCountingBloomFilterWithCounterBits(0.01, 1000, 4, 1)
+	fromString.Add("alpha")
+	fromBytes.AddBytes([]byte("alpha"))
+	assert.Equal(t, fromString.BloomFilter().Words(), fromBytes.BloomFilter().Words())
+	assert.True(t, fromBytes.Contains("alpha"))
+	assert.True(t, fromBytes.RemoveBytes([]byte("alpha")))
+	assert.False(t, fromBytes.ContainsBytes([]byte("alpha")))
+	assert.False(t, fromBytes.RemoveBytes([]byte("alpha")))
+
+	sbf := bloomfilter.NewScalableBloomFilterWithRatios(0.01, 10, 2, 0.8, 1)
+	for i := range uint64(100) {
+		sbf.AddBytes(binary.LittleEndian.AppendUint64(nil, i))
+	}
+	assert.Greater(t, len(sbf.Filters()), 1)
+	for i := range uint64(100) {
+		assert.True(t, sbf.ContainsBytes(binary.LittleEndian.AppendUint64(nil, i)))
+	}
+}
+
+func TestUint64DoesNotAllocate(t *testing.T) {
+	bf := bloomfilter.NewBloomFilter(0.01, 1000)
+	allocs := testing.AllocsPerRun(100, func() {
+		bf.AddUint64(42)
+		bf.ContainsUint64(42)
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestFilterDoesNotAllocate(t *testing.T) {
+	filter := bloomfilter.NewFilter(bloomfilter.NewBloomFilter(0.01, 1000), encodeUserID)
+	key := userID{shard: 3, id: 7}
+	allocs := testing.AllocsPerRun(100, func() {
+		filter.Add(key)
+		filter.Contains(key)
+	})
+	assert.Zero(t, allocs)
+}
+
+func BenchmarkAddUint64(b *testing.B) {
+	bf := bloomfilter.NewBloomFilter(0.01, 1000000)
+	b.ReportAllocs()
+	for i := range b.N {
+		bf.AddUint64(uint64(i))
+	}
+}
@@ -0,0 +1,19 @@
+package bloomfilter
+
+// Membership is implemented by every filter in this package. Contains may
+// report false positives but never false negatives for items that were
+// added and not since removed.
+type Membership interface {
+	Contains(item string) bool
+	ContainsBytes(data []byte) bool
+}
+
+var (
+	_ Membership = (*BloomFilter)(nil)
+	_ Membership = (*ConcurrentBloomFilter)(nil)
+	_ Membership = (*CountingBloomFilter)(nil)
+	_ Membership = (*ScalableBloomFilter)(nil)
+	_ Membership = (*CuckooFilter)(nil)
+	_ Membership = (*BinaryFuseFilter)(nil)
+	_ Membership = (*RotatingBloomFilter)(nil)
+)
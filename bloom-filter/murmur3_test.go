@@ -0,0 +1,28 @@
+package bloomfilter
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/spaolacci/murmur3"
+)
+
+// Filters built before murmur3Sum128 used the murmur3 package directly, so
+// both must agree on every input length.
+func TestMurmur3MatchesPackage(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for length := range 80 {
+		data := make([]byte, length)
+		rng.Read(data)
+		seed := rng.Uint32()
+
+		h1, h2 := murmur3Sum128(data, seed)
+		w1, w2 := murmur3.Sum128WithSeed(data, seed)
+		if h1 != w1 || h2 != w2 {
+			t.Errorf("length %d: got %x %x, want %x %x", length, h1, h2, w1, w2)
+		}
+		if sum := murmur3.Sum64WithSeed(data, seed); h1 != sum {
+			t.Errorf("length %d: got %x, want Sum64WithSeed %x", length, h1, sum)
+		}
+	}
+}
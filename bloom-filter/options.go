@@ -0,0 +1,132 @@
+package bloomfilter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrInvalidOptions is wrapped by every error about filter parameters.
+var ErrInvalidOptions = errors.New("bloomfilter: invalid options")
+
+// maxWords is the largest bitset make will allocate: the runtime refuses
+// allocations above 1<<48 bytes, so bigger filters panic in newBitset.
+const maxWords = (1<<48 - 1) / 8
+
+// maxBits is the largest m whose bitset fits in maxWords words.
+const maxBits = maxWords * 64
+
+// OptimalM returns the number of bits for n items at false positive rate p,
+// m = ceil(-n ln p / (ln 2)^2). It returns 0 for n <= 0 or p outside (0, 1).
+func OptimalM(n int, p float64) int {
+	if checkCapacity(p, n) != nil {
+		return 0
+	}
+	return int(math.Ceil(optimalM(n, p)))
+}
+
+// OptimalK returns the number of hash functions that minimises the false
+// positive rate of m bits holding n items, k = ceil(m/n ln 2). It returns 0
+// for m <= 0 or n <= 0.
+func OptimalK(m, n int) int {
+	if m <= 0 || n <= 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(m) / float64(n) * math.Log(2)))
+}
+
+func optimalM(n int, p float64) float64 {
+	return -float64(n) * math.Log(p) / (math.Log(2) * math.Log(2))
+}
+
+// Options configures NewBloomFilterWithOptions.
+type Options struct {
+	// Capacity is the number of items the filter is sized for.
+	Capacity int
+	// FalsePositiveRate is the rate the filter should stay below until it
+	// holds Capacity items, strictly between 0 and 1.
+	FalsePositiveRate float64
+	// HashScheme maps items to bits; zero means HashMurmur3Seeded.
+	HashScheme HashScheme
+	// Seed derives the hash seeds when Seeds is empty. Filters built with
+	// equal options set the same bits for the same items.
+	Seed uint64
+	// Seeds are used as the hash seeds as is, for example to rebuild a
+	// filter from another's Seeds. There must be as many as the scheme
+	// needs.
+	Seeds []uint32
+}
+
+// NewBloomFilterWithOptions returns a filter configured by opts, or an
+// error wrapping ErrInvalidOptions if they make no sense.
+func NewBloomFilterWithOptions(opts Options) (*BloomFilter, error) {
+	if err := checkCapacity(opts.FalsePositiveRate, opts.Capacity); err != nil {
+		return nil, err
+	}
+	m := optimalM(opts.Capacity, opts.FalsePositiveRate)
+	if m > maxBits {
+		return nil, fmt.Errorf("%w: %d items at rate %v need too many bits", ErrInvalidOptions, opts.Capacity, opts.FalsePositiveRate)
+	}
+	mInt := int(math.Ceil(m))
+	return newBloomFilterWithSeeds(mInt, OptimalK(mInt, opts.Capacity), opts.HashScheme, opts.Seed, opts.Seeds)
+}
+
+// NewBloomFilterWithSize returns a filter of exactly m bits and k hash
+// functions, with hash seeds derived from seed, or an error wrapping
+// ErrInvalidOptions.
+func NewBloomFilterWithSize(m, k int, scheme HashScheme, seed uint64) (*BloomFilter, error) {
+	if m <= 0 || m > maxBits {
+		return nil, fmt.Errorf("%w: m=%d", ErrInvalidOptions, m)
+	}
+	if k <= 0 {
+		return nil, fmt.Errorf("%w: k=%d", ErrInvalidOptions, k)
+	}
+	return newBloomFilterWithSeeds(m, k, scheme, seed, nil)
+}
+
+func newBloomFilterWithSeeds(m, k int, scheme HashScheme, seed uint64, seeds []uint32) (*BloomFilter, error) {
+	if scheme == 0 {
+		scheme = HashMurmur3Seeded
+	}
+	if !scheme.valid() {
+		return nil, fmt.Errorf("%w: unknown hash scheme %v", ErrInvalidOptions, scheme)
+	}
+
+	if len(seeds) == 0 {
+		seeds = deriveSeeds(seed, scheme.seedCount(k))
+	} else if len(seeds) != scheme.seedCount(k) {
+		return nil, fmt.Errorf("%w: got %d seeds, %v with k=%d needs %d", ErrInvalidOptions, len(seeds), scheme, k, scheme.seedCount(k))
+	} else {
+		seeds = slices.Clone(seeds)
+	}
+
+	return newBloomFilter(int64(m), int64(k), scheme, seeds), nil
+}
+
+// checkCapacity checks the n and p that the other constructors size
+// filters from.
+func checkCapacity(p float64, n int) error {
+	if n <= 0 {
+		return fmt.Errorf("%w: capacity %d is not positive", ErrInvalidOptions, n)
+	}
+	if !(p > 0 && p < 1) {
+		return fmt.Errorf("%w: false positive rate %v is not between 0 and 1", ErrInvalidOptions, p)
+	}
+	return nil
+}
+
+// optimalParams returns m and k for n items at rate p. It panics on
+// parameters NewBloomFilterWithOptions would reject.
+func optimalParams(p float64, n int) (m_int, k_int int64) {
+	if err := checkCapacity(p, n); err != nil {
+		panic(err)
+	}
+	m := optimalM(n, p)
+	if m > maxBits {
+		panic(fmt.Errorf("%w: %d items at rate %v need too many bits", ErrInvalidOptions, n, p))
+	}
+	m_int = int64(math.Ceil(m))
+	k_int = int64(OptimalK(int(m_int), n))
+	return m_int, k_int
+}
@@ -0,0 +1,89 @@
+package bloomfilter_test
+
+import (
+	"math"
+	"testing"
+
+	bloomfilter "dsalgo/bloom-filter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimalMAndK(t *testing.T) {
+	m := bloomfilter.OptimalM(1000000, 0.01)
+	assert.Equal(t, 9585059, m)
+	assert.Equal(t, 7, bloomfilter.OptimalK(m, 1000000))
+	assert.Equal(t, bloomfilter.NewBloomFilter(0.01, 1000000).Size(), m)
+
+	assert.Zero(t, bloomfilter.OptimalM(0, 0.01))
+	assert.Zero(t, bloomfilter.OptimalM(100, 1))
+	assert.Zero(t, bloomfilter.OptimalK(0, 100))
+}
+
+func TestNewBloomFilterWithOptions(t *testing.T) {
+	bf, err := bloomfilter.NewBloomFilterWithOptions(bloomfilter.Options{
+		Capacity:          1000,
+		FalsePositiveRate: 0.01,
+		Seed:              42,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, bloomfilter.HashMurmur3Seeded, bf.HashScheme())
+	assert.Equal(t, bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 42).HashConfig(), bf.HashConfig())
+
+	km, err := bloomfilter.NewBloomFilterWithOptions(bloomfilter.Options{
+		Capacity:          1000,
+		FalsePositiveRate: 0.01,
+		HashScheme:        bloomfilter.HashMurmur3KM,
+		Seeds:             []uint32{7},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{7}, km.Seeds())
+
+	km, err = bloomfilter.NewBloomFilterWithOptions(bloomfilter.Options{
+		Capacity:          1000,
+		FalsePositiveRate: 0.01,
+		HashScheme:        bloomfilter.HashMurmur3KM,
+		Seed:              3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, km.HashConfig(), bloomfilter.NewBloomFilterWithScheme(0.01, 1000, bloomfilter.HashMurmur3KM, 3).HashConfig())
+	assert.Panics(t, func() { bloomfilter.NewBloomFilterWithScheme(0.01, 1000, 9, 3) })
+
+	for name, opts := range map[string]bloomfilter.Options{
+		"zero capacity":      {Capacity: 0, FalsePositiveRate: 0.01},
+		"negative capacity":  {Capacity: -5, FalsePositiveRate: 0.01},
+		"zero rate":          {Capacity: 10, FalsePositiveRate: 0},
+		"rate of one":        {Capacity: 10, FalsePositiveRate: 1},
+		"NaN rate":           {Capacity: 10, FalsePositiveRate: math.NaN()},
+		"too large":          {Capacity: math.MaxInt, FalsePositiveRate: 1e-9},
+		"unallocatable":      {Capacity: 1 << 56, FalsePositiveRate: 0.01},
+		"just unallocatable": {Capacity: 1 << 48, FalsePositiveRate: 0.01},
+		"unknown scheme":     {Capacity: 10, FalsePositiveRate: 0.01, HashScheme: 9},
+		"wrong seed count":   {Capacity: 10, FalsePositiveRate: 0.01, Seeds: []uint32{1}},
+	} {
+		_, err := bloomfilter.NewBloomFilterWithOptions(opts)
+		assert.ErrorIs(t, err, bloomfilter.ErrInvalidOptions, name)
+	}
+
+	assert.Panics(t, func() { bloomfilter.NewBloomFilter(0.01, 0) })
+}
+
+func TestNewBloomFilterWithSize(t *testing.T) {
+	bf, err := bloomfilter.NewBloomFilterWithSize(1024, 3, bloomfilter.HashMurmur3KM, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1024, bf.Size())
+	assert.Equal(t, int64(3), bf.HashCount())
+	assert.Len(t, bf.Words(), 16)
+
+	_, err = bloomfilter.NewBloomFilterWithSize(0, 3, 0, 1)
+	assert.ErrorIs(t, err, bloomfilter.ErrInvalidOptions)
+	_, err = bloomfilter.NewBloomFilterWithSize(1024, 0, 0, 1)
+	assert.ErrorIs(t, err, bloomfilter.ErrInvalidOptions)
+
+	// make refuses more than 1<<48 bytes, so 1<<45 words is one too many
+	for _, m := range []int{1 << 51, 1 << 61} {
+		_, err = bloomfilter.NewBloomFilterWithSize(m, 3, 0, 1)
+		assert.ErrorIs(t, err, bloomfilter.ErrInvalidOptions, "m=%d", m)
+	}
+}
@@ -0,0 +1,162 @@
+package bloomfilter
+
+import (
+	"fmt"
+	"time"
+)
+
+// RotatingBloomFilter remembers items for a sliding window. It keeps N
+// generations of plain filters; new items go into the newest, Contains
+// checks them all, and each rotation clears the oldest generation and makes
+// it the newest. An item is therefore forgotten at the Nth rotation after
+// it was added: with an Interval, it is remembered for between N-1 and N
+// intervals.
+//
+// Rotation happens every Interval, every ItemsPerGeneration adds, or both.
+// Time based rotation is applied lazily by Add and Contains, so like
+// BloomFilter a RotatingBloomFilter is not safe for concurrent use.
+type RotatingBloomFilter struct {
+	generations []*BloomFilter // ring, newest at current
+	current     int
+	added       int // items added to the newest generation
+
+	interval  time.Duration
+	perGen    int
+	clock     func() time.Time
+	rotatedAt time.Time
+}
+
+// RotatingOptions configures NewRotatingBloomFilter.
+type RotatingOptions struct {
+	// Generations is the number of sub-filters, at least 1.
+	Generations int
+	// Capacity is the number of items each generation is sized for.
+	Capacity int
+	// FalsePositiveRate is the overall rate across all generations; each
+	// is sized for FalsePositiveRate / Generations.
+	FalsePositiveRate float64
+	// Interval rotates the generations on a timer; zero disables it.
+	Interval time.Duration
+	// ItemsPerGeneration rotates once that many items have been added to
+	// the newest generation; zero disables it.
+	ItemsPerGeneration int
+	// Clock returns the current time; nil means time.Now.
+	Clock func() time.Time
+	// Seed derives the hash seeds, see Options.Seed.
+	Seed uint64
+}
+
+// NewRotatingBloomFilter returns a rotating filter configured by opts, or
+// an error wrapping ErrInvalidOptions.
+func NewRotatingBloomFilter(opts RotatingOptions) (*RotatingBloomFilter, error) {
+	if opts.Generations < 1 {
+		return nil, fmt.Errorf("%w: %d generations", ErrInvalidOptions, opts.Generations)
+	}
+	if opts.Interval < 0 || opts.ItemsPerGeneration < 0 || opts.Interval == 0 && opts.ItemsPerGeneration == 0 {
+		return nil, fmt.Errorf("%w: need a positive Interval or ItemsPerGeneration", ErrInvalidOptions)
+	}
+	if opts.Clock == nil {
+		opts.Clock = time.Now
+	}
+
+	generations := make([]*BloomFilter, opts.Generations)
+	for i := range generations {
+		bf, err := NewBloomFilterWithOptions(Options{
+			Capacity:          opts.Capacity,
+			FalsePositiveRate: opts.FalsePositiveRate / float64(opts.Generations),
+			Seed:              opts.Seed,
+		})
+		if err != nil {
+			return nil, err
+		}
+		generations[i] = bf
+	}
+
+	return &RotatingBloomFilter{
+		generations: generations,
+		interval:    opts.Interval,
+		perGen:      opts.ItemsPerGeneration,
+		clock:       opts.Clock,
+		rotatedAt:   opts.Clock(),
+	}, nil
+}
+
+func (rf *RotatingBloomFilter) Add(item string) {
+	rf.AddBytes([]byte(item))
+}
+
+func (rf *RotatingBloomFilter) Contains(item string) bool {
+	return rf.ContainsBytes([]byte(item))
+}
+
+// AddBytes adds data to the newest generation, rotating first if it is
+// due.
+func (rf *RotatingBloomFilter) AddBytes(data []byte) {
+	rf.advance()
+	if rf.perGen > 0 && rf.added >= rf.perGen {
+		rf.Rotate()
+	}
+	rf.generations[rf.current].AddBytes(data)
+	rf.added++
+}
+
+// ContainsBytes reports whether data may have been added to any live
+// generation.
+func (rf *RotatingBloomFilter) ContainsBytes(data []byte) bool {
+	rf.advance()
+	for _, bf := range rf.generations {
+		if bf.ContainsBytes(data) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rotate drops the oldest generation and starts a new one, whatever the
+// schedule. The time based schedule restarts from now.
+func (rf *RotatingBloomFilter) Rotate() {
+	rf.rotate()
+	rf.rotatedAt = rf.clock()
+}
+
+// Clear forgets every item.
+func (rf *RotatingBloomFilter) Clear() {
+	for _, bf := range rf.generations {
+		bf.Clear()
+	}
+	rf.added = 0
+	rf.rotatedAt = rf.clock()
+}
+
+// Generations returns the sub-filters, newest first.
+func (rf *RotatingBloomFilter) Generations() []*BloomFilter {
+	n := len(rf.generations)
+	generations := make([]*BloomFilter, n)
+	for i := range generations {
+		generations[i] = rf.generations[(rf.current-i+n)%n]
+	}
+	return generations
+}
+
+func (rf *RotatingBloomFilter) rotate() {
+	rf.current = (rf.current + 1) % len(rf.generations)
+	rf.generations[rf.current].Clear()
+	rf.added = 0
+}
+
+// advance applies the rotations the timer has called for since the last
+// one. Rotations are counted from the schedule rather than from when they
+// were noticed, so an idle filter does not drift.
+func (rf *RotatingBloomFilter) advance() {
+	if rf.interval <= 0 {
+		return
+	}
+	due := rf.clock().Sub(rf.rotatedAt) / rf.interval
+	if due <= 0 {
+		return
+	}
+	for range min(int64(due), int64(len(rf.generations))) {
+		rf.rotate()
+	}
+	rf.rotatedAt = rf.rotatedAt.Add(due * rf.interval)
+}
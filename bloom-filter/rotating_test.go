@@ -0,0 +1,92 @@
+package bloomfilter_test
+
+import (
+	"testing"
+	"time"
+
+	bloomfilter "dsalgo/bloom-filter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestRotatingByTime(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rf, err := bloomfilter.NewRotatingBloomFilter(bloomfilter.RotatingOptions{
+		Generations:       3,
+		Capacity:          1000,
+		FalsePositiveRate: 0.01,
+		Interval:          20 * time.Minute,
+		Clock:             clock.Now,
+		Seed:              1,
+	})
+	require.NoError(t, err)
+	require.Len(t, rf.Generations(), 3)
+
+	rf.Add("early")
+	clock.now = clock.now.Add(30 * time.Minute)
+	rf.Add("late")
+	assert.True(t, rf.Contains("early"))
+	assert.True(t, rf.Contains("late"))
+
+	// 60 minutes in: "early" went in before the first rotation at 20
+	// minutes and is dropped by the third, at 60.
+	clock.now = clock.now.Add(30 * time.Minute)
+	assert.False(t, rf.Contains("early"))
+	assert.True(t, rf.Contains("late"))
+
+	// Idle for longer than the whole window forgets everything.
+	clock.now = clock.now.Add(5 * time.Hour)
+	assert.False(t, rf.Contains("late"))
+}
+
+func TestRotatingByCount(t *testing.T) {
+	rf, err := bloomfilter.NewRotatingBloomFilter(bloomfilter.RotatingOptions{
+		Generations:        2,
+		Capacity:           10,
+		FalsePositiveRate:  0.001,
+		ItemsPerGeneration: 2,
+		Seed:               1,
+	})
+	require.NoError(t, err)
+
+	for _, item := range []string{"a", "b", "c", "d"} {
+		rf.Add(item)
+	}
+	assert.True(t, rf.Contains("a"))
+	assert.True(t, rf.Contains("d"))
+
+	rf.Add("e")
+	assert.False(t, rf.Contains("a"))
+	assert.False(t, rf.Contains("b"))
+	assert.True(t, rf.Contains("c"))
+	assert.True(t, rf.Contains("e"))
+	assert.True(t, rf.Generations()[0].Contains("e"))
+
+	rf.Rotate()
+	rf.Rotate()
+	assert.False(t, rf.Contains("e"))
+
+	rf.Add("f")
+	rf.Clear()
+	assert.False(t, rf.Contains("f"))
+}
+
+func TestRotatingOptionsValidation(t *testing.T) {
+	for name, opts := range map[string]bloomfilter.RotatingOptions{
+		"no generations": {Generations: 0, Capacity: 10, FalsePositiveRate: 0.01, Interval: time.Second},
+		"no schedule":    {Generations: 2, Capacity: 10, FalsePositiveRate: 0.01},
+		"bad capacity":   {Generations: 2, Capacity: 0, FalsePositiveRate: 0.01, Interval: time.Second},
+	} {
+		_, err := bloomfilter.NewRotatingBloomFilter(opts)
+		assert.ErrorIs(t, err, bloomfilter.ErrInvalidOptions, name)
+	}
+}
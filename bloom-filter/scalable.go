@@ -0,0 +1,132 @@
+package bloomfilter
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+const (
+	// DefaultGrowthFactor is the size ratio between successive sub-filters.
+	DefaultGrowthFactor = 2
+	// DefaultTighteningRatio is the false positive ratio between successive
+	// sub-filters; Almeida et al. suggest 0.8 to 0.9.
+	DefaultTighteningRatio = 0.85
+)
+
+// ScalableBloomFilter is a Bloom filter that grows as items arrive
+// (Almeida et al., "Scalable Bloom Filters"). It chains plain filters: once
+// the newest one holds as many items as it was sized for, a larger one with
+// a tighter false positive rate is added. With sub-filter rates p0, p0*r,
+// p0*r^2, ... the overall rate stays below p0 / (1 - r) however many items
+// are added.
+type ScalableBloomFilter struct {
+	filters    []*BloomFilter
+	capacities []int
+	count      int // items added to the newest filter
+
+	p       float64
+	n       int
+	growth  float64
+	tighten float64
+	seed    uint64
+}
+
+// NewScalableBloomFilter returns a filter with an overall false positive
+// rate of at most p, starting out sized for n items, growing by
+// DefaultGrowthFactor with DefaultTighteningRatio.
+func NewScalableBloomFilter(p float64, n int) *ScalableBloomFilter {
+	return NewScalableBloomFilterWithRatios(p, n, DefaultGrowthFactor, DefaultTighteningRatio, rand.Uint64())
+}
+
+// NewScalableBloomFilterWithRatios is like NewScalableBloomFilter, but with
+// the given growth factor (at least 1) and tightening ratio (between 0 and
+// 1), and hash seeds derived from seed. It panics if either ratio is out of
+// range.
+func NewScalableBloomFilterWithRatios(p float64, n int, growth, tightening float64, seed uint64) *ScalableBloomFilter {
+	if growth < 1 || tightening <= 0 || tightening >= 1 {
+		panic(fmt.Sprintf("bloomfilter: invalid growth factor %v or tightening ratio %v", growth, tightening))
+	}
+	sbf := &ScalableBloomFilter{
+		// The sub-filter rates sum to at most p0 / (1 - r), so start at
+		// p0 = p * (1 - r) to keep the total within p.
+		p:       p * (1 - tightening),
+		n:       n,
+		growth:  growth,
+		tighten: tightening,
+		seed:    seed,
+	}
+	sbf.grow()
+	return sbf
+}
+
+func (sbf *ScalableBloomFilter) Add(item string) {
+	sbf.AddBytes([]byte(item))
+}
+
+// AddBytes adds data to the newest sub-filter, growing first if it is full.
+func (sbf *ScalableBloomFilter) AddBytes(data []byte) {
+	if sbf.count >= sbf.capacities[len(sbf.capacities)-1] {
+		sbf.grow()
+	}
+	sbf.filters[len(sbf.filters)-1].AddBytes(data)
+	sbf.count++
+}
+
+func (sbf *ScalableBloomFilter) Contains(item string) bool {
+	return sbf.ContainsBytes([]byte(item))
+}
+
+// ContainsBytes reports whether data may have been added to any sub-filter.
+func (sbf *ScalableBloomFilter) ContainsBytes(data []byte) bool {
+	for _, bf := range sbf.filters {
+		if bf.ContainsBytes(data) {
+			return true
+		}
+	}
+	return false
+}
+
+// Clear drops every sub-filter but a fresh first one.
+func (sbf *ScalableBloomFilter) Clear() {
+	sbf.filters = nil
+	sbf.capacities = nil
+	sbf.grow()
+}
+
+// Size returns the total number of bits across all sub-filters.
+func (sbf *ScalableBloomFilter) Size() int {
+	size := 0
+	for _, bf := range sbf.filters {
+		size += bf.Size()
+	}
+	return size
+}
+
+// Filters returns the sub-filters, oldest first.
+func (sbf *ScalableBloomFilter) Filters() []*BloomFilter {
+	return sbf.filters
+}
+
+// FalsePositiveBound returns the bound on the overall false positive rate,
+// the sum of the sub-filter rates.
+func (sbf *ScalableBloomFilter) FalsePositiveBound() float64 {
+	bound := 0.0
+	for i := range sbf.filters {
+		bound += sbf.p * math.Pow(sbf.tighten, float64(i))
+	}
+	return bound
+}
+
+// grow appends a sub-filter for growth times as many items as the last,
+// at tightening times its false positive rate.
+func (sbf *ScalableBloomFilter) grow() {
+	i := len(sbf.filters)
+	capacity := int(math.Ceil(float64(sbf.n) * math.Pow(sbf.growth, float64(i))))
+	p := sbf.p * math.Pow(sbf.tighten, float64(i))
+	// Each sub-filter gets its own seeds so their false positives are
+	// independent.
+	sbf.filters = append(sbf.filters, NewBloomFilterWithSeed(p, capacity, sbf.seed+uint64(i)))
+	sbf.capacities = append(sbf.capacities, capacity)
+	sbf.count = 0
+}
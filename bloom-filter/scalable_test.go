@@ -0,0 +1,49 @@
+package bloomfilter_test
+
+import (
+	"fmt"
+	"testing"
+
+	bloomfilter "dsalgo/bloom-filter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScalableGrowsPastCapacity(t *testing.T) {
+	const (
+		p = 0.01
+		n = 1000
+	)
+	sbf := bloomfilter.NewScalableBloomFilterWithRatios(p, n, 2, 0.8, 1)
+	require.Len(t, sbf.Filters(), 1)
+
+	for i := range 20 * n {
+		sbf.Add(fmt.Sprintf("item-%d", i))
+	}
+	// 1000 + 2000 + 4000 + 8000 < 20000 <= 1000 + ... + 16000
+	assert.Len(t, sbf.Filters(), 5)
+	assert.LessOrEqual(t, sbf.FalsePositiveBound(), p)
+
+	for i := range 20 * n {
+		require.True(t, sbf.Contains(fmt.Sprintf("item-%d", i)))
+	}
+
+	const probes = 100000
+	falsePositives := 0
+	for i := range probes {
+		if sbf.Contains(fmt.Sprintf("other-%d", i)) {
+			falsePositives++
+		}
+	}
+	assert.LessOrEqual(t, float64(falsePositives)/probes, p)
+
+	sbf.Clear()
+	assert.Len(t, sbf.Filters(), 1)
+	assert.False(t, sbf.Contains("item-0"))
+}
+
+func TestScalableRejectsBadRatios(t *testing.T) {
+	assert.Panics(t, func() { bloomfilter.NewScalableBloomFilterWithRatios(0.01, 10, 0.5, 0.8, 1) })
+	assert.Panics(t, func() { bloomfilter.NewScalableBloomFilterWithRatios(0.01, 10, 2, 1, 1) })
+}
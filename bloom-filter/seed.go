@@ -0,0 +1,16 @@
+package bloomfilter
+
+// deriveSeeds expands seed into k hash seeds with splitmix64. The sequence
+// is fixed so that filters built from the same seed stay compatible.
+func deriveSeeds(seed uint64, k int) []uint32 {
+	seeds := make([]uint32, k)
+	for i := range seeds {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		z ^= z >> 31
+		seeds[i] = uint32(z >> 32)
+	}
+	return seeds
+}
@@ -0,0 +1,101 @@
+package bloomfilter
+
+import (
+	"fmt"
+	"slices"
+)
+
+// HashConfig is everything that decides which bits an item sets. Filters
+// can only be combined if their configs are equal.
+type HashConfig struct {
+	M      uint64     // number of bits
+	K      int        // number of hash functions
+	Scheme HashScheme // how items are mapped to bits
+	Seeds  []uint32   // hash seeds, see BloomFilter.Seeds
+}
+
+// Equal reports whether c and other map every item to the same bits.
+func (c HashConfig) Equal(other HashConfig) bool {
+	return c.M == other.M && c.K == other.K && c.Scheme == other.Scheme && slices.Equal(c.Seeds, other.Seeds)
+}
+
+// IncompatibleError is returned when combining filters whose hash configs
+// differ.
+type IncompatibleError struct {
+	A, B HashConfig
+}
+
+func (e *IncompatibleError) Error() string {
+	switch {
+	case e.A.M != e.B.M:
+		return fmt.Sprintf("bloomfilter: incompatible filters: m=%d and m=%d", e.A.M, e.B.M)
+	case e.A.K != e.B.K:
+		return fmt.Sprintf("bloomfilter: incompatible filters: k=%d and k=%d", e.A.K, e.B.K)
+	case e.A.Scheme != e.B.Scheme:
+		return fmt.Sprintf("bloomfilter: incompatible filters: scheme %v and %v", e.A.Scheme, e.B.Scheme)
+	default:
+		return "bloomfilter: incompatible filters: different hash seeds"
+	}
+}
+
+// HashConfig returns the filter's hash configuration.
+func (bf *BloomFilter) HashConfig() HashConfig {
+	return HashConfig{M: bf.m, K: int(bf.hashCount), Scheme: bf.scheme, Seeds: slices.Clone(bf.seeds)}
+}
+
+// Union returns a new filter containing the items of both bf and other.
+func (bf *BloomFilter) Union(other *BloomFilter) (*BloomFilter, error) {
+	union := bf.clone()
+	if err := union.UnionInPlace(other); err != nil {
+		return nil, err
+	}
+	return union, nil
+}
+
+// UnionInPlace adds the items of other to bf. The result is exactly the
+// filter that adding both sets of items would have built.
+func (bf *BloomFilter) UnionInPlace(other *BloomFilter) error {
+	if err := bf.checkCompatible(other); err != nil {
+		return err
+	}
+	for i, word := range other.bits {
+		bf.bits[i] |= word
+	}
+	return nil
+}
+
+// Intersect returns a new filter containing the items of both bf and
+// other.
+func (bf *BloomFilter) Intersect(other *BloomFilter) (*BloomFilter, error) {
+	intersection := bf.clone()
+	if err := intersection.IntersectInPlace(other); err != nil {
+		return nil, err
+	}
+	return intersection, nil
+}
+
+// IntersectInPlace keeps only the bits set in both bf and other. Every item
+// in both sets is still reported, but the false positive rate can be higher
+// than that of a filter built from the intersection directly.
+func (bf *BloomFilter) IntersectInPlace(other *BloomFilter) error {
+	if err := bf.checkCompatible(other); err != nil {
+		return err
+	}
+	for i, word := range other.bits {
+		bf.bits[i] &= word
+	}
+	return nil
+}
+
+func (bf *BloomFilter) checkCompatible(other *BloomFilter) error {
+	if a, b := bf.HashConfig(), other.HashConfig(); !a.Equal(b) {
+		return &IncompatibleError{A: a, B: b}
+	}
+	return nil
+}
+
+func (bf *BloomFilter) clone() *BloomFilter {
+	clone := newBloomFilter(int64(bf.m), bf.hashCount, bf.scheme, slices.Clone(bf.seeds))
+	copy(clone.bits, bf.bits)
+	return clone
+}
@@ -0,0 +1,56 @@
+package bloomfilter_test
+
+import (
+	"testing"
+
+	bloomfilter "dsalgo/bloom-filter"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnionAndIntersect(t *testing.T) {
+	a := bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 5)
+	b := bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 5)
+	both := bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 5)
+	for _, word := range []string{"alpha", "bravo", "shared"} {
+		a.Add(word)
+		both.Add(word)
+	}
+	for _, word := range []string{"charlie", "delta", "shared"} {
+		b.Add(word)
+		both.Add(word)
+	}
+
+	union, err := a.Union(b)
+	require.NoError(t, err)
+	assert.Equal(t, both.Words(), union.Words())
+	assert.False(t, a.Contains("charlie"), "Union must not modify its receiver")
+
+	intersection, err := a.Intersect(b)
+	require.NoError(t, err)
+	assert.True(t, intersection.Contains("shared"))
+	assert.False(t, intersection.Contains("alpha"))
+
+	require.NoError(t, a.UnionInPlace(b))
+	assert.Equal(t, both.Words(), a.Words())
+	require.NoError(t, a.IntersectInPlace(intersection))
+	assert.Equal(t, intersection.Words(), a.Words())
+}
+
+func TestCombiningIncompatibleFilters(t *testing.T) {
+	a := bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 5)
+	for _, other := range []*bloomfilter.BloomFilter{
+		bloomfilter.NewBloomFilterWithSeed(0.01, 2000, 5),
+		bloomfilter.NewBloomFilterWithSeed(0.01, 1000, 6),
+		bloomfilter.NewBloomFilterWithScheme(0.01, 1000, bloomfilter.HashMurmur3KM, 5),
+	} {
+		_, err := a.Union(other)
+		var incompatible *bloomfilter.IncompatibleError
+		require.ErrorAs(t, err, &incompatible)
+		assert.Equal(t, a.HashConfig(), incompatible.A)
+		assert.Equal(t, other.HashConfig(), incompatible.B)
+
+		assert.ErrorAs(t, a.IntersectInPlace(other), &incompatible)
+	}
+}
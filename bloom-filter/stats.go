@@ -0,0 +1,53 @@
+package bloomfilter
+
+import "math"
+
+// FillRatio returns the fraction of bits that are set.
+func (bf *BloomFilter) FillRatio() float64 {
+	return fillRatio(bf.bits.count(), bf.m)
+}
+
+// EstimatedCount estimates how many distinct items have been added from the
+// number of set bits (Swamidass and Baldi). It is +Inf once every bit is
+// set.
+func (bf *BloomFilter) EstimatedCount() float64 {
+	return estimatedCount(bf.bits.count(), bf.m, bf.hashCount)
+}
+
+// FalsePositiveRate returns the probability that Contains reports an item
+// that was never added, given the bits set right now.
+func (bf *BloomFilter) FalsePositiveRate() float64 {
+	return falsePositiveRate(bf.bits.count(), bf.m, bf.hashCount)
+}
+
+// FillRatio returns the fraction of bits that are set.
+func (bf *ConcurrentBloomFilter) FillRatio() float64 {
+	return fillRatio(bf.bits.count(), bf.m)
+}
+
+// EstimatedCount estimates how many distinct items have been added, as
+// BloomFilter.EstimatedCount does.
+func (bf *ConcurrentBloomFilter) EstimatedCount() float64 {
+	return estimatedCount(bf.bits.count(), bf.m, int64(bf.k))
+}
+
+// FalsePositiveRate returns the probability that Contains reports an item
+// that was never added, given the bits set right now.
+func (bf *ConcurrentBloomFilter) FalsePositiveRate() float64 {
+	return falsePositiveRate(bf.bits.count(), bf.m, int64(bf.k))
+}
+
+func fillRatio(set, m uint64) float64 {
+	return float64(set) / float64(m)
+}
+
+// estimatedCount returns n* = -(m/k) ln(1 - X/m) for X set bits.
+func estimatedCount(set, m uint64, k int64) float64 {
+	return -float64(m) / float64(k) * math.Log1p(-fillRatio(set, m))
+}
+
+// falsePositiveRate returns (X/m)^k, the chance that k independent bit
+// positions are all set.
+func falsePositiveRate(set, m uint64, k int64) float64 {
+	return math.Pow(fillRatio(set, m), float64(k))
+}
@@ -0,0 +1,48 @@
+package bloomfilter_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	bloomfilter "dsalgo/bloom-filter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatistics(t *testing.T) {
+	const (
+		p = 0.01
+		n = 10000
+	)
+	bf := bloomfilter.NewBloomFilterWithSeed(p, n, 3)
+	assert.Equal(t, 0.0, bf.FillRatio())
+	assert.Equal(t, 0.0, bf.EstimatedCount())
+	assert.Equal(t, 0.0, bf.FalsePositiveRate())
+
+	for i := range n {
+		bf.Add(fmt.Sprintf("item-%d", i))
+	}
+
+	// At capacity an optimally sized filter is about half full and runs at
+	// close to its planned rate.
+	assert.InDelta(t, 0.5, bf.FillRatio(), 0.02)
+	assert.InDelta(t, n, bf.EstimatedCount(), n*0.02)
+	assert.InDelta(t, p, bf.FalsePositiveRate(), p*0.3)
+	assert.Equal(t, bf.EstimatedCount(), bf.Concurrent().EstimatedCount())
+
+	// Overfilling shows up as a rising rate.
+	for i := range n {
+		bf.Add(fmt.Sprintf("extra-%d", i))
+	}
+	assert.InDelta(t, 2*n, bf.EstimatedCount(), 2*n*0.03)
+	assert.Greater(t, bf.FalsePositiveRate(), 5*p)
+
+	full := bloomfilter.NewBloomFilterWithSeed(0.5, 1, 1)
+	for i := range 100 {
+		full.Add(fmt.Sprintf("item-%d", i))
+	}
+	assert.Equal(t, 1.0, full.FillRatio())
+	assert.True(t, math.IsInf(full.EstimatedCount(), 1))
+	assert.Equal(t, 1.0, full.FalsePositiveRate())
+}
@@ -0,0 +1,230 @@
+// Command bloom builds, saves, queries and inspects Bloom filters using the
+// bloomfilter package.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+
+	bloomfilter "dsalgo/bloom-filter"
+)
+
+// Exit codes for queries, so scripts can branch on the result.
+const (
+	exitPresent = 0 // every key may be present
+	exitAbsent  = 1 // at least one key is certainly absent
+	exitError   = 2
+)
+
+func main() {
+	// Define flags
+	var (
+		saveFile   = flag.String("save", "", "Build a filter from newline delimited keys in a file (or stdin) and save it here")
+		loadFile   = flag.String("load", "", "Path to a saved filter to query or inspect")
+		capacity   = flag.Int("capacity", 0, "Number of items to size the filter for (0 means the number of keys read)")
+		fpr        = flag.Float64("fpr", 0.01, "Target false positive rate")
+		hashScheme = flag.String("hash-scheme", bloomfilter.HashMurmur3Seeded.String(), "Hashing: murmur3-seeded or murmur3-km (one pass double hashing)")
+		seed       = flag.Uint64("seed", 0, "Seed for the hash functions (0 picks a random one)")
+		showStats  = flag.Bool("stats", false, "Print m, k, fill ratio and estimated count")
+		quiet      = flag.Bool("q", false, "Do not print query results, only set the exit code")
+		showHelp   = flag.Bool("h", false, "Show help message")
+	)
+
+	flag.Parse()
+
+	if *showHelp {
+		fmt.Println("Bloom Filter CLI Tool")
+		fmt.Println("Usage:")
+		fmt.Println("  Build from a file:  go run ./cmd/bloom -save=keys.bloom [-capacity=N] [-fpr=0.01] keys.txt")
+		fmt.Println("  Build from stdin:   cat keys.txt | go run ./cmd/bloom -save=keys.bloom")
+		fmt.Println("  Query keys:         go run ./cmd/bloom -load=keys.bloom key1 key2")
+		fmt.Println("  Query from stdin:   cat queries.txt | go run ./cmd/bloom -load=keys.bloom")
+		fmt.Println("  Print stats:        go run ./cmd/bloom -load=keys.bloom -stats")
+		fmt.Println("")
+		fmt.Println("Queries exit with 0 if every key may be present, 1 if any key is")
+		fmt.Println("certainly absent and 2 on errors. Empty lines in key input are skipped.")
+		fmt.Println("")
+		fmt.Println("Flags:")
+		flag.PrintDefaults()
+		return
+	}
+
+	args := flag.Args()
+
+	switch {
+	case *saveFile != "":
+		scheme, err := bloomfilter.ParseHashScheme(*hashScheme)
+		if err != nil {
+			fatal(err)
+		}
+		keys, err := readKeysFrom(args)
+		if err != nil {
+			fatal(fmt.Errorf("reading keys: %v", err))
+		}
+		bf, err := buildFilter(keys, *capacity, *fpr, scheme, *seed)
+		if err != nil {
+			fatal(err)
+		}
+		if err := saveFilter(bf, *saveFile); err != nil {
+			fatal(fmt.Errorf("saving filter: %v", err))
+		}
+		fmt.Printf("✅ Saved filter with %d keys to %s\n", len(keys), *saveFile)
+		if *showStats {
+			fmt.Println()
+			printStats(bf)
+		}
+
+	case *loadFile != "":
+		bf, err := loadFilter(*loadFile)
+		if err != nil {
+			fatal(fmt.Errorf("loading filter: %v", err))
+		}
+		if *showStats {
+			printStats(bf)
+			if len(args) == 0 {
+				return
+			}
+			fmt.Println()
+		}
+
+		keys := args
+		if len(keys) == 0 {
+			keys, err = readKeys(os.Stdin)
+			if err != nil {
+				fatal(fmt.Errorf("reading keys: %v", err))
+			}
+		}
+		os.Exit(query(bf, keys, *quiet))
+
+	default:
+		fmt.Println("Nothing to do, use -save to build a filter or -load to query one (-h for help)")
+		os.Exit(exitError)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	os.Exit(exitError)
+}
+
+// readKeysFrom reads keys from the named file, or stdin if there is none
+// or it is "-".
+func readKeysFrom(args []string) ([]string, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("expected a single key file, got %d", len(args))
+	}
+	if len(args) == 0 || args[0] == "-" {
+		return readKeys(os.Stdin)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readKeys(f)
+}
+
+// readKeys returns the non-empty lines of r, without line endings.
+func readKeys(r io.Reader) ([]string, error) {
+	var keys []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		key := strings.TrimSuffix(scanner.Text(), "\r")
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys, scanner.Err()
+}
+
+func buildFilter(keys []string, capacity int, fpr float64, scheme bloomfilter.HashScheme, seed uint64) (*bloomfilter.BloomFilter, error) {
+	if capacity == 0 {
+		capacity = max(len(keys), 1)
+	}
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+
+	bf, err := bloomfilter.NewBloomFilterWithOptions(bloomfilter.Options{
+		Capacity:          capacity,
+		FalsePositiveRate: fpr,
+		HashScheme:        scheme,
+		Seed:              seed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		bf.Add(key)
+	}
+	return bf, nil
+}
+
+func saveFilter(bf *bloomfilter.BloomFilter, filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if _, err := bf.WriteTo(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func loadFilter(filename string) (*bloomfilter.BloomFilter, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var bf bloomfilter.BloomFilter
+	if _, err := bf.ReadFrom(bufio.NewReader(f)); err != nil {
+		return nil, err
+	}
+	return &bf, nil
+}
+
+// query prints whether each key may be in the filter and returns the exit
+// code.
+func query(bf *bloomfilter.BloomFilter, keys []string, quiet bool) int {
+	code := exitPresent
+	for _, key := range keys {
+		present := bf.Contains(key)
+		if !present {
+			code = exitAbsent
+		}
+		if quiet {
+			continue
+		}
+		if present {
+			fmt.Printf("✅ maybe present: %s\n", key)
+		} else {
+			fmt.Printf("❌ absent:        %s\n", key)
+		}
+	}
+	return code
+}
+
+func printStats(bf *bloomfilter.BloomFilter) {
+	fmt.Println("=== Bloom Filter ===")
+	fmt.Printf("Bits (m):            %d (%d bytes)\n", bf.Size(), len(bf.Words())*8)
+	fmt.Printf("Hash Functions (k):  %d\n", bf.HashCount())
+	fmt.Printf("Hash Scheme:         %s\n", bf.HashScheme())
+	fmt.Printf("Fill Ratio:          %.4f\n", bf.FillRatio())
+	fmt.Printf("Estimated Count:     %.0f\n", bf.EstimatedCount())
+	fmt.Printf("False Positive Rate: %.6f\n", bf.FalsePositiveRate())
+}
@@ -0,0 +1,335 @@
+// Command merkle builds, saves, compares and proves Merkle trees over files
+// and directories using the merkletree package.
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	merkletree "dsalgo/merkle_tree"
+)
+
+func main() {
+	// Define flags
+	var (
+		compareJSON = flag.String("compare", "", "Path to JSON file containing previous Merkle tree for comparison")
+		saveJSON    = flag.String("save", "", "Path to save current Merkle tree as JSON")
+		loadJSON    = flag.String("load", "", "Path to load Merkle tree from JSON file")
+		proveFile   = flag.String("prove", "", "Print an inclusion proof for this file after building the tree")
+		verifyJSON  = flag.String("verify", "", "Path to a JSON inclusion proof to verify against -root")
+		rootHex     = flag.String("root", "", "Hex encoded root hash used by -verify")
+		hashScheme  = flag.String("hash-scheme", string(merkletree.HashSchemeLegacy), "Leaf/node hashing: legacy or prefixed (RFC 6962 0x00/0x01 prefixes)")
+		oddNodes    = flag.String("odd-nodes", string(merkletree.OddNodeDuplicate), "Odd level handling: duplicate, promote or split (RFC 6962)")
+		leafMode    = flag.String("leaf-mode", string(merkletree.LeafContent), "What leaves commit to: content, path or path+mode")
+		dirTree     = flag.Bool("dirs", false, "Build a directory shaped tree and print the hash of every directory")
+		subdir      = flag.String("subdir", "", "With -dirs, only print the directory at this path")
+		maxSize     = flag.Int64("max-size", 0, "Reject files larger than this many bytes (0 means no limit)")
+		diffJSON    = flag.Bool("json", false, "Print the -compare file diff as JSON")
+		timeout     = flag.Duration("timeout", merkletree.DefaultHashPolicy.Timeout, "Time limit for hashing all files (0 means no limit)")
+		showHelp    = flag.Bool("h", false, "Show help message")
+	)
+
+	flag.Parse()
+
+	if *showHelp {
+		fmt.Println("Merkle Tree CLI Tool")
+		fmt.Println("Usage:")
+		fmt.Println("  Build from files:     go run ./cmd/merkle [files...]")
+		fmt.Println("  Build from directory: go run ./cmd/merkle [directory]")
+		fmt.Println("  Compare with JSON:    go run ./cmd/merkle -compare=old.json [files...]")
+		fmt.Println("  Save to JSON:         go run ./cmd/merkle -save=tree.json [files...]")
+		fmt.Println("  Load from JSON:       go run ./cmd/merkle -load=tree.json")
+		fmt.Println("  Prove a file:         go run ./cmd/merkle -prove=dir/a.txt [directory]")
+		fmt.Println("  Verify a proof:       go run ./cmd/merkle -verify=proof.json -root=<hex> [file]")
+		fmt.Println("  Directory hashes:     go run ./cmd/merkle -dirs [-subdir=src] [directory]")
+		fmt.Println("")
+		fmt.Println("Flags:")
+		flag.PrintDefaults()
+		return
+	}
+
+	args := flag.Args()
+
+	// Handle load JSON case
+	if *loadJSON != "" {
+		tree, err := merkletree.Load(*loadJSON)
+		if err != nil {
+			fmt.Printf("Error loading JSON: %v\n", err)
+			return
+		}
+
+		fmt.Println("=== Loaded Merkle Tree ===")
+		printRoot(tree)
+		fmt.Printf("File Count: %d\n", tree.FileCount)
+		fmt.Printf("Created At: %s\n", tree.CreatedAt.Format(time.RFC3339))
+
+		if len(tree.Manifest) > 0 {
+			fmt.Println("\n=== Manifest ===")
+			for _, entry := range tree.Manifest {
+				fmt.Printf("%s %12d bytes  %s  %s\n", entry.Mode, entry.Size, entry.Hash, entry.Path)
+			}
+		}
+		return
+	}
+
+	// Handle proof verification case
+	if *verifyJSON != "" {
+		ok, err := verifyProofFile(*verifyJSON, *rootHex, args)
+		if err != nil {
+			fmt.Printf("Error verifying proof: %v\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			fmt.Println("❌ Proof is INVALID")
+			os.Exit(1)
+		}
+		fmt.Println("✅ Proof is VALID")
+		return
+	}
+
+	// Build new tree from files
+	var data []merkletree.HashResult
+	var err error
+
+	policy := merkletree.DefaultHashPolicy
+	policy.MaxFileSize = *maxSize
+	policy.Timeout = *timeout
+
+	if len(args) > 1 {
+		data, err = merkletree.HashFiles(args, policy)
+		if err != nil {
+			fmt.Printf("Error getting direct filepaths: %v\n", err)
+			return
+		}
+	} else if len(args) == 1 {
+		data, err = merkletree.HashDirectory(args[0], policy)
+		if err != nil {
+			fmt.Printf("Error hashing files: %v\n", err)
+			return
+		}
+	} else {
+		fmt.Println("No files provided")
+		return
+	}
+
+	if *dirTree {
+		tree, err := merkletree.BuildDirTreeFromResults(data)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		printDirTree(tree, *subdir)
+		return
+	}
+
+	scheme, err := merkletree.ParseHashScheme(*hashScheme)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	strategy, err := merkletree.ParseOddNodeStrategy(*oddNodes)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	mode, err := merkletree.ParseLeafMode(*leafMode)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	tree := merkletree.BuildFromResults(data, merkletree.TreeOptions{
+		HashScheme:      scheme,
+		OddNodeStrategy: strategy,
+		LeafMode:        mode,
+	})
+	if tree == nil {
+		fmt.Println("Could not build Merkle Tree")
+		return
+	}
+
+	fmt.Println("=== New Merkle Tree ===")
+	printRoot(tree)
+	fmt.Printf("File Count: %d\n", tree.FileCount)
+	fmt.Printf("Created At: %s\n", tree.CreatedAt.Format(time.RFC3339))
+
+	var totalBytes int64
+	fmt.Println("\n=== Hashed Files ===")
+	for _, result := range data {
+		fmt.Printf("%12d bytes  %s\n", result.Size, result.Path)
+		totalBytes += result.Size
+	}
+	fmt.Printf("Total Bytes: %d\n", totalBytes)
+
+	// Print an inclusion proof if requested
+	if *proveFile != "" {
+		proof, err := tree.ProofForPath(*proveFile)
+		if err != nil {
+			fmt.Printf("Error generating proof: %v\n", err)
+			return
+		}
+		proofJSON, err := json.MarshalIndent(proof, "", "  ")
+		if err != nil {
+			fmt.Printf("Error serializing proof: %v\n", err)
+			return
+		}
+		fmt.Println()
+		fmt.Println(string(proofJSON))
+	}
+
+	// Save to JSON if requested
+	if *saveJSON != "" {
+		err := tree.Save(*saveJSON)
+		if err != nil {
+			fmt.Printf("Error saving JSON: %v\n", err)
+		} else {
+			fmt.Printf("✅ Saved tree to %s\n", *saveJSON)
+		}
+	}
+
+	// Compare with existing JSON if requested
+	if *compareJSON != "" {
+		fmt.Println()
+		oldTree, err := merkletree.Load(*compareJSON)
+		if err != nil {
+			fmt.Printf("Error loading comparison JSON: %v\n", err)
+			return
+		}
+
+		printComparison(tree, oldTree)
+
+		diff, diffErr := tree.Compare(oldTree)
+		if *diffJSON {
+			if diffErr != nil {
+				fmt.Printf("Error diffing trees: %v\n", diffErr)
+				return
+			}
+			diffData, err := diff.ToJSON()
+			if err != nil {
+				fmt.Printf("Error serializing diff: %v\n", err)
+				return
+			}
+			fmt.Println(string(diffData))
+			return
+		}
+
+		// Show detailed comparison
+		if !tree.Equal(oldTree) || (diffErr == nil && !diff.IsEmpty()) {
+			fmt.Println("\n=== Detailed Analysis ===")
+			if tree.FileCount != oldTree.FileCount {
+				fmt.Printf("📊 File count changed: %d → %d\n", oldTree.FileCount, tree.FileCount)
+			}
+			if diffErr != nil {
+				fmt.Printf("Cannot list changed files: %v\n", diffErr)
+			} else {
+				printDiff(diff)
+			}
+		}
+	}
+}
+
+func printRoot(tree *merkletree.MerkleTree) {
+	fmt.Printf("Merkle Tree Root Hash: %s\n", hex.EncodeToString(tree.Root.Hash))
+}
+
+func printDirTree(tree *merkletree.DirTree, subdir string) {
+	node := tree.Root
+	if subdir != "" {
+		node = tree.Lookup(subdir)
+		if node == nil {
+			fmt.Printf("Error: %s is not part of the tree\n", subdir)
+			return
+		}
+	}
+
+	fmt.Println("=== Directory Merkle Tree ===")
+	fmt.Printf("Root Hash: %s\n", hex.EncodeToString(node.Hash))
+	fmt.Printf("File Count: %d\n", tree.FileCount)
+	fmt.Println()
+
+	var walk func(node *merkletree.DirNode)
+	walk = func(node *merkletree.DirNode) {
+		if !node.IsDir {
+			return
+		}
+		fmt.Printf("%s  %s/\n", hex.EncodeToString(node.Hash), node.Path)
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(node)
+}
+
+func printComparison(tree, other *merkletree.MerkleTree) {
+	fmt.Println("=== Merkle Tree Comparison ===")
+
+	if tree.Equal(other) {
+		fmt.Println("✅ Trees are IDENTICAL")
+		fmt.Printf("Root Hash: %s\n", hex.EncodeToString(tree.Root.Hash))
+		return
+	}
+
+	fmt.Println("❌ Trees are DIFFERENT")
+
+}
+
+func printDiff(d *merkletree.TreeDiff) {
+	if d.IsEmpty() {
+		fmt.Println("No file changes")
+		return
+	}
+	for _, path := range d.Added {
+		fmt.Printf("➕ Added:    %s\n", path)
+	}
+	for _, path := range d.Removed {
+		fmt.Printf("➖ Removed:  %s\n", path)
+	}
+	for _, path := range d.Modified {
+		fmt.Printf("✏️  Modified: %s\n", path)
+	}
+	fmt.Printf("📊 %d added, %d removed, %d modified\n", len(d.Added), len(d.Removed), len(d.Modified))
+}
+
+func verifyProofFile(proofFile, rootHex string, files []string) (bool, error) {
+	if rootHex == "" {
+		return false, fmt.Errorf("a root hash is required, use -root")
+	}
+	rootHash, err := hex.DecodeString(rootHex)
+	if err != nil {
+		return false, fmt.Errorf("invalid root hash: %v", err)
+	}
+
+	jsonData, err := os.ReadFile(proofFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	var proof merkletree.MerkleProof
+	if err := json.Unmarshal(jsonData, &proof); err != nil {
+		return false, fmt.Errorf("failed to parse JSON: %v", err)
+	}
+
+	leafHash := proof.LeafHash
+	if len(files) > 0 {
+		result, err := merkletree.HashFile(context.Background(), files[0], merkletree.DefaultHashPolicy)
+		if err != nil {
+			return false, err
+		}
+		scheme, err := merkletree.ParseHashScheme(string(proof.HashScheme))
+		if err != nil {
+			return false, err
+		}
+		mode, err := merkletree.ParseLeafMode(string(proof.LeafMode))
+		if err != nil {
+			return false, err
+		}
+		leafHash = scheme.LeafHash(mode.LeafData(proof.Path, result.Mode, result.Hash))
+	}
+
+	return merkletree.VerifyProof(leafHash, &proof, rootHash), nil
+}
@@ -5,6 +5,7 @@ go 1.24.4
 require (
 	github.com/spaolacci/murmur3 v1.1.0
 	github.com/stretchr/testify v1.10.0
+	golang.org/x/text v0.26.0
 )
 
 require (
@@ -0,0 +1,130 @@
+package merkletree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// TreeDiff lists the files that changed between two trees, by path.
+type TreeDiff struct {
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+	Modified []string `json:"modified"`
+}
+
+// IsEmpty reports whether no file changed.
+func (d *TreeDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// ToJSON serializes the diff as indented JSON.
+func (d *TreeDiff) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// Compare reports the files added, removed and modified going from other to m.
+// Both trees are walked together and subtrees with equal hashes are skipped;
+// only the leaves under differing subtrees are matched up by path.
+func (m *MerkleTree) Compare(other *MerkleTree) (*TreeDiff, error) {
+	if len(m.Manifest) != m.FileCount || len(other.Manifest) != other.FileCount {
+		return nil, fmt.Errorf("both trees need a manifest to be diffed")
+	}
+
+	w := &diffWalk{newTree: m, oldTree: other}
+	w.walk(m.Root, other.Root, 0, 0)
+
+	diff := &TreeDiff{Added: []string{}, Removed: []string{}, Modified: []string{}}
+	for path, hash := range w.newLeaves {
+		oldHash, ok := w.oldLeaves[path]
+		if !ok {
+			diff.Added = append(diff.Added, path)
+		} else if hash != oldHash {
+			diff.Modified = append(diff.Modified, path)
+		}
+	}
+	for path := range w.oldLeaves {
+		if _, ok := w.newLeaves[path]; !ok {
+			diff.Removed = append(diff.Removed, path)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Modified)
+
+	return diff, nil
+}
+
+// diffWalk collects the content hashes, keyed by path, of the leaves that
+// sit under subtrees whose hashes differ between the two trees.
+type diffWalk struct {
+	newTree, oldTree     *MerkleTree
+	newLeaves, oldLeaves map[string]string
+}
+
+func (w *diffWalk) walk(a, b *MerkleNode, offA, offB int) {
+	if a != nil && b != nil && bytes.Equal(a.Hash, b.Hash) {
+		// Same content in the same shape; only renames and padding can hide here
+		count := leafCount(a)
+		for i := range count {
+			realA := offA+i < w.newTree.FileCount
+			realB := offB+i < w.oldTree.FileCount
+			if !realA && !realB {
+				break
+			}
+			// A padding copy on one side can stand in for a real file on
+			// the other, as with [a,b,c] and [a,b,c,c] under duplication
+			if realA != realB || w.newTree.Manifest[offA+i].Path != w.oldTree.Manifest[offB+i].Path {
+				w.collect(a, b, offA, offB)
+				return
+			}
+		}
+		return
+	}
+
+	if a != nil && b != nil && !isLeaf(a) && !isLeaf(b) {
+		firstA, secondA := w.newTree.OddNodeStrategy.children(a)
+		firstB, secondB := w.oldTree.OddNodeStrategy.children(b)
+		w.walk(firstA, firstB, offA, offB)
+		w.walk(secondA, secondB, offA+leafCount(firstA), offB+leafCount(firstB))
+		return
+	}
+
+	w.collect(a, b, offA, offB)
+}
+
+func (w *diffWalk) collect(a, b *MerkleNode, offA, offB int) {
+	if w.newLeaves == nil {
+		w.newLeaves = map[string]string{}
+		w.oldLeaves = map[string]string{}
+	}
+	for i := range leafCount(a) {
+		if offA+i < w.newTree.FileCount {
+			entry := w.newTree.Manifest[offA+i]
+			w.newLeaves[entry.Path] = entry.Hash
+		}
+	}
+	for i := range leafCount(b) {
+		if offB+i < w.oldTree.FileCount {
+			entry := w.oldTree.Manifest[offB+i]
+			w.oldLeaves[entry.Path] = entry.Hash
+		}
+	}
+}
+
+func isLeaf(node *MerkleNode) bool {
+	return node.Left == nil && node.Right == nil
+}
+
+// leafCount counts the leaves under node, including padding copies.
+func leafCount(node *MerkleNode) int {
+	if node == nil {
+		return 0
+	}
+	if isLeaf(node) {
+		return 1
+	}
+	return leafCount(node.Left) + leafCount(node.Right)
+}
@@ -0,0 +1,131 @@
+package merkletree
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func buildTestTree(files map[string]string) *MerkleTree {
+	return buildTestTreeWithOptions(files, TreeOptions{})
+}
+
+func buildTestTreeWithOptions(files map[string]string, opts TreeOptions) *MerkleTree {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	results := make([]HashResult, 0, len(paths))
+	for _, path := range paths {
+		results = append(results, HashResult{File: path, Hash: []byte(files[path])})
+	}
+	return BuildFromResults(results, opts)
+}
+
+func TestDiff(t *testing.T) {
+	oldTree := buildTestTree(map[string]string{
+		"a": "1", "b": "2", "c": "3", "d": "4", "e": "5",
+	})
+	newTree := buildTestTree(map[string]string{
+		"a": "1", "b": "2", "c": "changed", "e": "5", "f": "6",
+	})
+
+	diff, err := newTree.Compare(oldTree)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	want := &TreeDiff{Added: []string{"f"}, Removed: []string{"d"}, Modified: []string{"c"}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("unexpected diff: got %+v, want %+v", diff, want)
+	}
+}
+
+func TestDiffAcrossStrategies(t *testing.T) {
+	oldFiles := map[string]string{"a": "1", "b": "2", "c": "3", "d": "4", "e": "5", "f": "6", "g": "7"}
+	newFiles := map[string]string{"a": "1", "b": "2", "c": "3", "d": "4", "e": "5", "f": "changed", "g": "7"}
+
+	for _, strategy := range allStrategies {
+		opts := TreeOptions{OddNodeStrategy: strategy}
+		diff, err := buildTestTreeWithOptions(newFiles, opts).Compare(buildTestTreeWithOptions(oldFiles, opts))
+		if err != nil {
+			t.Fatalf("%s: Compare failed: %v", strategy, err)
+		}
+		if !reflect.DeepEqual(diff.Modified, []string{"f"}) || len(diff.Added) != 0 || len(diff.Removed) != 0 {
+			t.Errorf("%s: unexpected diff: %+v", strategy, diff)
+		}
+	}
+}
+
+func TestDiffIdenticalTrees(t *testing.T) {
+	files := map[string]string{"a": "1", "b": "2", "c": "3"}
+	diff, err := buildTestTree(files).Compare(buildTestTree(files))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if !diff.IsEmpty() {
+		t.Errorf("expected an empty diff, got %+v", diff)
+	}
+}
+
+func TestDiffDetectsRenames(t *testing.T) {
+	oldTree := buildTestTree(map[string]string{"a": "1", "b": "2"})
+	newTree := buildTestTree(map[string]string{"a": "1", "c": "2"})
+
+	diff, err := newTree.Compare(oldTree)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	want := &TreeDiff{Added: []string{"c"}, Removed: []string{"b"}, Modified: []string{}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("unexpected diff: got %+v, want %+v", diff, want)
+	}
+}
+
+func TestDiffDetectsFileMatchingPadding(t *testing.T) {
+	opts := TreeOptions{OddNodeStrategy: OddNodeDuplicate}
+	oldTree := buildTestTreeWithOptions(map[string]string{"a": "1", "b": "2", "c": "3"}, opts)
+	newTree := buildTestTreeWithOptions(map[string]string{"a": "1", "b": "2", "c": "3", "d": "3"}, opts)
+
+	diff, err := newTree.Compare(oldTree)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	want := &TreeDiff{Added: []string{"d"}, Removed: []string{}, Modified: []string{}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("unexpected diff: got %+v, want %+v", diff, want)
+	}
+
+	diff, err = oldTree.Compare(newTree)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	want = &TreeDiff{Added: []string{}, Removed: []string{"d"}, Modified: []string{}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("unexpected diff: got %+v, want %+v", diff, want)
+	}
+}
+
+func TestDiffLoadedTree(t *testing.T) {
+	oldTree := buildTestTree(map[string]string{"a": "1", "b": "2", "c": "3"})
+	filename := filepath.Join(t.TempDir(), "tree.json")
+	if err := oldTree.Save(filename); err != nil {
+		t.Fatalf("SaveToFile failed: %v", err)
+	}
+	loaded, err := Load(filename)
+	if err != nil {
+		t.Fatalf("LoadMerkleTreeFromFile failed: %v", err)
+	}
+
+	newTree := buildTestTree(map[string]string{"a": "1", "b": "changed", "c": "3"})
+	diff, err := newTree.Compare(loaded)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if !reflect.DeepEqual(diff.Modified, []string{"b"}) || len(diff.Added) != 0 || len(diff.Removed) != 0 {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+}
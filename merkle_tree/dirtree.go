@@ -0,0 +1,195 @@
+package merkletree
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DirNode is a file or a directory of a DirTree. A directory's hash covers
+// the name, type and hash of each of its entries, sorted by name.
+type DirNode struct {
+	Name     string     `json:"name"`
+	Path     string     `json:"path"` // slash separated, relative to the tree root; "." for the root
+	IsDir    bool       `json:"is_dir"`
+	Hash     []byte     `json:"hash"`
+	Size     int64      `json:"size,omitempty"`
+	Children []*DirNode `json:"children,omitempty"`
+}
+
+// DirTree is a Merkle tree shaped like the directory it was built from, in
+// the style of git tree objects. As in git, empty directories are left out.
+type DirTree struct {
+	Root      *DirNode  `json:"root"`
+	CreatedAt time.Time `json:"created_at"`
+	FileCount int       `json:"file_count"`
+	RootHash  string    `json:"root_hash"`
+}
+
+const (
+	dirEntryFile byte = 'f'
+	dirEntryDir  byte = 'd'
+)
+
+// BuildDirTree hashes every file below directory and builds a DirTree.
+func BuildDirTree(directory string, policy HashPolicy) (*DirTree, error) {
+	results, err := HashDirectory(directory, policy)
+	if err != nil {
+		return nil, err
+	}
+	return BuildDirTreeFromResults(results)
+}
+
+// BuildDirTreeFromResults builds a DirTree from hashed files, placing each
+// file by its relative Path. It fails if a path is absolute or climbs out
+// of the tree root, as the File of a result from HashFile may.
+func BuildDirTreeFromResults(results []HashResult) (*DirTree, error) {
+	root := &DirNode{Path: ".", IsDir: true}
+	dirs := map[string]*DirNode{".": root}
+
+	for _, result := range results {
+		filePath := path.Clean(newManifestEntry(result).Path)
+		if path.IsAbs(filePath) || filePath == "." || filePath == ".." || strings.HasPrefix(filePath, "../") {
+			return nil, fmt.Errorf("%s is not a path below the tree root", filePath)
+		}
+		parent := ensureDir(dirs, path.Dir(filePath))
+		parent.Children = append(parent.Children, &DirNode{
+			Name: path.Base(filePath),
+			Path: filePath,
+			Hash: result.Hash,
+			Size: result.Size,
+		})
+	}
+
+	hashDirNode(root)
+
+	return &DirTree{
+		Root:      root,
+		CreatedAt: time.Now(),
+		FileCount: len(results),
+		RootHash:  hex.EncodeToString(root.Hash),
+	}, nil
+}
+
+// Lookup returns the node at the given slash separated path, or nil.
+func (t *DirTree) Lookup(nodePath string) *DirNode {
+	nodePath = path.Clean(strings.TrimPrefix(nodePath, "./"))
+	if nodePath == "." || nodePath == "" {
+		return t.Root
+	}
+
+	node := t.Root
+	for _, name := range strings.Split(nodePath, "/") {
+		i := sort.Search(len(node.Children), func(i int) bool {
+			return node.Children[i].Name >= name
+		})
+		if i == len(node.Children) || node.Children[i].Name != name {
+			return nil
+		}
+		node = node.Children[i]
+	}
+	return node
+}
+
+// Compare reports the files added, removed and modified going from other to
+// t. Directories with equal hashes are skipped without being walked.
+func (t *DirTree) Compare(other *DirTree) *TreeDiff {
+	diff := &TreeDiff{Added: []string{}, Removed: []string{}, Modified: []string{}}
+	compareDirNodes(t.Root, other.Root, diff)
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Modified)
+
+	return diff
+}
+
+func compareDirNodes(a, b *DirNode, diff *TreeDiff) {
+	if bytes.Equal(a.Hash, b.Hash) && a.IsDir == b.IsDir {
+		return
+	}
+
+	if !a.IsDir && !b.IsDir {
+		diff.Modified = append(diff.Modified, a.Path)
+		return
+	}
+	if !a.IsDir || !b.IsDir {
+		diff.Added = appendFiles(diff.Added, a)
+		diff.Removed = appendFiles(diff.Removed, b)
+		return
+	}
+
+	// Both directories; children are sorted by name so merge them
+	i, j := 0, 0
+	for i < len(a.Children) || j < len(b.Children) {
+		switch {
+		case j == len(b.Children) || i < len(a.Children) && a.Children[i].Name < b.Children[j].Name:
+			diff.Added = appendFiles(diff.Added, a.Children[i])
+			i++
+		case i == len(a.Children) || b.Children[j].Name < a.Children[i].Name:
+			diff.Removed = appendFiles(diff.Removed, b.Children[j])
+			j++
+		default:
+			compareDirNodes(a.Children[i], b.Children[j], diff)
+			i++
+			j++
+		}
+	}
+}
+
+// appendFiles appends the paths of all files at or below node.
+func appendFiles(paths []string, node *DirNode) []string {
+	if !node.IsDir {
+		return append(paths, node.Path)
+	}
+	for _, child := range node.Children {
+		paths = appendFiles(paths, child)
+	}
+	return paths
+}
+
+func ensureDir(dirs map[string]*DirNode, dirPath string) *DirNode {
+	if dir, ok := dirs[dirPath]; ok {
+		return dir
+	}
+	if dirPath == "." || dirPath == "/" {
+		return dirs["."]
+	}
+
+	parent := ensureDir(dirs, path.Dir(dirPath))
+	dir := &DirNode{Name: path.Base(dirPath), Path: dirPath, IsDir: true}
+	parent.Children = append(parent.Children, dir)
+	dirs[dirPath] = dir
+	return dir
+}
+
+// hashDirNode sorts the entries of every directory below node and fills in
+// their hashes, bottom up.
+func hashDirNode(node *DirNode) {
+	sort.Slice(node.Children, func(i, j int) bool {
+		return node.Children[i].Name < node.Children[j].Name
+	})
+
+	hash := sha256.New()
+	for _, child := range node.Children {
+		entryType := dirEntryFile
+		if child.IsDir {
+			hashDirNode(child)
+			entryType = dirEntryDir
+		}
+		node.Size += child.Size
+
+		// type, length prefixed name, child hash
+		hash.Write([]byte{entryType})
+		hash.Write(binary.AppendUvarint(nil, uint64(len(child.Name))))
+		hash.Write([]byte(child.Name))
+		hash.Write(child.Hash)
+	}
+	node.Hash = hash.Sum(nil)
+}
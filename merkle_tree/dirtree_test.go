@@ -0,0 +1,116 @@
+package merkletree
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		writeTestFile(t, filepath.Dir(filename), filepath.Base(filename), []byte(content))
+	}
+}
+
+func buildDirTree(t *testing.T, results []HashResult) *DirTree {
+	t.Helper()
+	tree, err := BuildDirTreeFromResults(results)
+	if err != nil {
+		t.Fatalf("BuildDirTreeFromResults failed: %v", err)
+	}
+	return tree
+}
+
+func TestDirTreeLocalisesChanges(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"README":       "readme",
+		"src/main.go":  "package main",
+		"src/lib/a.go": "package lib",
+		"docs/guide":   "guide",
+	})
+
+	before, err := BuildDirTree(dir, DefaultHashPolicy)
+	if err != nil {
+		t.Fatalf("BuildDirTree failed: %v", err)
+	}
+	if before.FileCount != 4 || before.Lookup("src/lib/a.go") == nil || before.Lookup("src/missing") != nil {
+		t.Fatalf("unexpected tree shape: %+v", before.Root)
+	}
+
+	writeTestFile(t, filepath.Join(dir, "src", "lib"), "a.go", []byte("package lib // changed"))
+	after, err := BuildDirTree(dir, DefaultHashPolicy)
+	if err != nil {
+		t.Fatalf("BuildDirTree failed: %v", err)
+	}
+
+	for _, unchanged := range []string{"docs", "README", "src/main.go"} {
+		if !bytes.Equal(before.Lookup(unchanged).Hash, after.Lookup(unchanged).Hash) {
+			t.Errorf("hash of %s changed", unchanged)
+		}
+	}
+	for _, changed := range []string{".", "src", "src/lib"} {
+		if bytes.Equal(before.Lookup(changed).Hash, after.Lookup(changed).Hash) {
+			t.Errorf("hash of %s did not change", changed)
+		}
+	}
+
+	diff := after.Compare(before)
+	want := &TreeDiff{Added: []string{}, Removed: []string{}, Modified: []string{"src/lib/a.go"}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("unexpected diff: got %+v, want %+v", diff, want)
+	}
+}
+
+func TestDirTreeCoversNames(t *testing.T) {
+	a := buildDirTree(t, []HashResult{{Path: "x/a", Hash: []byte("1")}, {Path: "x/b", Hash: []byte("2")}})
+	b := buildDirTree(t, []HashResult{{Path: "x/a", Hash: []byte("1")}, {Path: "x/c", Hash: []byte("2")}})
+	c := buildDirTree(t, []HashResult{{Path: "y/a", Hash: []byte("1")}, {Path: "y/b", Hash: []byte("2")}})
+
+	if a.RootHash == b.RootHash {
+		t.Error("renaming a file did not change the root hash")
+	}
+	if a.RootHash == c.RootHash {
+		t.Error("renaming a directory did not change the root hash")
+	}
+	if !bytes.Equal(a.Lookup("x").Hash, c.Lookup("y").Hash) {
+		t.Error("directories with the same entries should share a hash")
+	}
+
+	diff := b.Compare(a)
+	want := &TreeDiff{Added: []string{"x/c"}, Removed: []string{"x/b"}, Modified: []string{}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("unexpected diff: got %+v, want %+v", diff, want)
+	}
+}
+
+func TestDirTreeFileReplacedByDirectory(t *testing.T) {
+	a := buildDirTree(t, []HashResult{{Path: "x", Hash: []byte("1")}})
+	b := buildDirTree(t, []HashResult{{Path: "x/y", Hash: []byte("1")}})
+
+	diff := b.Compare(a)
+	want := &TreeDiff{Added: []string{"x/y"}, Removed: []string{"x"}, Modified: []string{}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("unexpected diff: got %+v, want %+v", diff, want)
+	}
+}
+
+func TestDirTreeRejectsPathsOutsideRoot(t *testing.T) {
+	for _, result := range []HashResult{
+		{File: "/srv/a.txt", Hash: []byte("1")},
+		{Path: "/a.txt", Hash: []byte("1")},
+		{Path: "../a.txt", Hash: []byte("1")},
+		{Path: "x/../../a.txt", Hash: []byte("1")},
+	} {
+		if _, err := BuildDirTreeFromResults([]HashResult{result}); err == nil {
+			t.Errorf("expected an error for %+v", result)
+		}
+	}
+}
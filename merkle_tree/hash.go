@@ -0,0 +1,305 @@
+package merkletree
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+func getAllFilesInDirectory(directory string) ([]string, error) {
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	filenames := make([]string, 0, len(files))
+
+	for _, file := range files {
+		fullPath := filepath.Join(directory, file.Name())
+		fullPath = filepath.Clean(fullPath)
+		fullPath, err = filepath.Abs(fullPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if file.IsDir() {
+			subFiles, err := getAllFilesInDirectory(fullPath)
+			if err != nil {
+				return nil, err
+			}
+			filenames = append(filenames, subFiles...)
+		} else {
+			filenames = append(filenames, fullPath)
+		}
+	}
+
+	return filenames, nil
+}
+
+// HashDirectory hashes every file below directory. Results carry canonical
+// paths relative to directory and are sorted by them.
+func HashDirectory(directory string, policy HashPolicy) ([]HashResult, error) {
+
+	filenames, err := getAllFilesInDirectory(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := hashFiles(filenames, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, setRelativePaths(results, directory)
+}
+
+// HashFiles hashes the given files, none of which may be a directory.
+// Results carry canonical paths relative to the deepest directory
+// containing all of them and are sorted by them.
+func HashFiles(filenames []string, policy HashPolicy) ([]HashResult, error) {
+
+	directFilePaths := make([]string, 0, len(filenames))
+
+	for _, filename := range filenames {
+
+		fileInfo, err := os.Stat(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		if fileInfo.IsDir() {
+			return nil, fmt.Errorf("cannot hash directories along with filepaths")
+		}
+		absPath, err := filepath.Abs(filename)
+		if err != nil {
+			return nil, err
+		}
+		directFilePaths = append(directFilePaths, absPath)
+	}
+
+	results, err := hashFiles(directFilePaths, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, setRelativePaths(results, commonDir(directFilePaths))
+}
+
+func hashFiles(files []string, policy HashPolicy) ([]HashResult, error) {
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files provided")
+	}
+
+	// sort the files
+	sort.Strings(files)
+
+	return hashFilesWithPolicy(files, policy)
+}
+
+// HashResult is the outcome of hashing a single file.
+type HashResult struct {
+	File string
+	Path string // File relative to the hashed root, see setRelativePaths
+	Hash []byte
+	Size int64 // bytes actually read while hashing
+	Mode os.FileMode
+}
+
+// HashPolicy controls how file contents are read while hashing.
+type HashPolicy struct {
+	// WholeFileLimit is the largest file that is read into memory at once.
+	// Anything bigger is streamed in ChunkSize pieces.
+	WholeFileLimit int64
+	ChunkSize      int
+	// MaxFileSize rejects files larger than this many bytes; 0 means no limit.
+	MaxFileSize int64
+	// Timeout bounds hashing of the whole file set; 0 means no limit.
+	Timeout time.Duration
+}
+
+// DefaultHashPolicy reads files up to 5MB in one go, streams anything
+// larger in 1MB chunks and limits neither file size nor run time.
+var DefaultHashPolicy = HashPolicy{
+	WholeFileLimit: 5 * 1024 * 1024,
+	ChunkSize:      1024 * 1024,
+	MaxFileSize:    0,
+	Timeout:        0,
+}
+
+func hashFilesWithPolicy(files []string, policy HashPolicy) ([]HashResult, error) {
+	workers := min(len(files), runtime.NumCPU())
+
+	jobs := make(chan string, len(files))
+	results := make(chan HashResult, len(files))
+	errors := make(chan error, len(files))
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if policy.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), policy.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+
+	wg := sync.WaitGroup{}
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case job, ok := <-jobs:
+					if !ok {
+						return // jobs channel closed
+					}
+					result, err := HashFile(ctx, job, policy)
+					if err != nil {
+						errors <- err
+						cancel()
+						return
+					}
+					results <- result
+				case <-ctx.Done():
+					return // Context cancelled, stop worker
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, file := range files {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- file:
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+		close(errors)
+	}()
+
+	var hashedFiles []HashResult
+	expectedResults := len(files)
+	receivedResults := 0
+
+	for receivedResults < expectedResults {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				// if the results channel is closed, check if we got all results
+				if receivedResults < expectedResults {
+					return nil, fmt.Errorf("not all files processed successfully")
+				}
+				break
+			}
+			hashedFiles = append(hashedFiles, result)
+			receivedResults++
+		case err, ok := <-errors:
+			if !ok {
+				// errors is closed together with results once all workers
+				// exit; stop selecting on it and drain the buffered results
+				errors = nil
+				continue
+			}
+			cancel()
+			return nil, err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	sort.Slice(hashedFiles, func(i, j int) bool {
+		return hashedFiles[i].File < hashedFiles[j].File
+	})
+
+	return hashedFiles, nil
+}
+
+// HashFile returns the sha256 of the file content together with the number
+// of bytes that went into it and the file mode. Files larger than
+// policy.WholeFileLimit are streamed, checking ctx between chunks.
+func HashFile(ctx context.Context, file string, policy HashPolicy) (HashResult, error) {
+
+	select {
+	case <-ctx.Done():
+		return HashResult{}, ctx.Err()
+	default:
+		break
+	}
+
+	data, err := os.Open(file)
+	if err != nil {
+		return HashResult{}, err
+	}
+	defer data.Close()
+
+	stat, err := data.Stat()
+	if err != nil {
+		return HashResult{}, err
+	}
+
+	if stat.IsDir() {
+		return HashResult{}, fmt.Errorf("is a directory")
+	}
+
+	if policy.MaxFileSize > 0 && stat.Size() > policy.MaxFileSize {
+		return HashResult{}, fmt.Errorf("%s is %d bytes, above the %d byte limit", file, stat.Size(), policy.MaxFileSize)
+	}
+
+	hash := sha256.New()
+	var size int64
+
+	if stat.Size() <= policy.WholeFileLimit { // small files are read in one go
+		content, err := io.ReadAll(data)
+		if err != nil {
+			return HashResult{}, err
+		}
+		hash.Write(content)
+		size = int64(len(content))
+	} else { // everything else is streamed, whatever its size
+		chunkSize := policy.ChunkSize
+		if chunkSize <= 0 {
+			chunkSize = DefaultHashPolicy.ChunkSize
+		}
+		buffer := make([]byte, chunkSize)
+		for {
+			// Check if context is cancelled before each read
+			select {
+			case <-ctx.Done():
+				return HashResult{}, ctx.Err()
+			default:
+			}
+
+			n, err := data.Read(buffer)
+			if n > 0 {
+				hash.Write(buffer[:n])
+				size += int64(n)
+			}
+
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				return HashResult{}, err
+			}
+		}
+	}
+
+	return HashResult{File: file, Hash: hash.Sum(nil), Size: size, Mode: stat.Mode()}, nil
+}
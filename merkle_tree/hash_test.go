@@ -0,0 +1,121 @@
+package merkletree
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// smallPolicy streams anything above 16 bytes so the chunked path can be
+// exercised without writing huge files.
+var smallPolicy = HashPolicy{WholeFileLimit: 16, ChunkSize: 7, Timeout: 30 * time.Second}
+
+func writeTestFile(t *testing.T, dir, name string, content []byte) string {
+	t.Helper()
+	filename := filepath.Join(dir, name)
+	if err := os.WriteFile(filename, content, 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	return filename
+}
+
+func TestHashFileStreamsLargeFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := writeTestFile(t, dir, "a.dat", bytes.Repeat([]byte("a"), 100))
+	b := writeTestFile(t, dir, "b.dat", bytes.Repeat([]byte("b"), 100))
+
+	resultA, err := HashFile(context.Background(), a, smallPolicy)
+	if err != nil {
+		t.Fatalf("hashFileWithPolicy failed: %v", err)
+	}
+	resultB, err := HashFile(context.Background(), b, smallPolicy)
+	if err != nil {
+		t.Fatalf("hashFileWithPolicy failed: %v", err)
+	}
+
+	want := sha256.Sum256(bytes.Repeat([]byte("a"), 100))
+	if !bytes.Equal(resultA.Hash, want[:]) {
+		t.Errorf("streamed hash mismatch: got %x, want %x", resultA.Hash, want)
+	}
+	if resultA.Size != 100 {
+		t.Errorf("expected 100 bytes hashed, got %d", resultA.Size)
+	}
+	if bytes.Equal(resultA.Hash, resultB.Hash) {
+		t.Error("different large files produced the same hash")
+	}
+}
+
+func TestHashFileRejectsFilesAboveMaxSize(t *testing.T) {
+	filename := writeTestFile(t, t.TempDir(), "big.dat", make([]byte, 64))
+
+	policy := smallPolicy
+	policy.MaxFileSize = 32
+	if _, err := HashFile(context.Background(), filename, policy); err == nil {
+		t.Error("expected an error for a file above MaxFileSize")
+	}
+}
+
+func TestHashFileHonoursCancellation(t *testing.T) {
+	filename := writeTestFile(t, t.TempDir(), "big.dat", make([]byte, 64))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := HashFile(ctx, filename, smallPolicy); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestHashFilesReportsSizes(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a.dat", make([]byte, 10))
+	writeTestFile(t, dir, "b.dat", make([]byte, 1000))
+
+	results, err := HashDirectory(dir, smallPolicy)
+	if err != nil {
+		t.Fatalf("hashFilesInDirectory failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Size != 10 || results[1].Size != 1000 {
+		t.Errorf("unexpected sizes: %+v", results)
+	}
+}
+
+func TestHashFilesWithoutTimeout(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a.dat", make([]byte, 100))
+
+	policy := smallPolicy
+	policy.Timeout = 0
+	results, err := HashDirectory(dir, policy)
+	if err != nil {
+		t.Fatalf("HashDirectory with no timeout failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Size != 100 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestBuildFromPaths(t *testing.T) {
+	dir := t.TempDir()
+	a := writeTestFile(t, dir, "a.txt", []byte("alpha"))
+	b := writeTestFile(t, dir, "b.txt", []byte("bravo"))
+
+	fromDir, err := BuildFromPaths([]string{dir}, DefaultHashPolicy, TreeOptions{})
+	if err != nil {
+		t.Fatalf("BuildFromPaths(dir) failed: %v", err)
+	}
+	fromFiles, err := BuildFromPaths([]string{b, a}, DefaultHashPolicy, TreeOptions{})
+	if err != nil {
+		t.Fatalf("BuildFromPaths(files) failed: %v", err)
+	}
+	if !fromDir.Equal(fromFiles) {
+		t.Errorf("directory and file list roots differ: %s != %s", fromDir.RootHash, fromFiles.RootHash)
+	}
+
+	if _, err := BuildFromPaths(nil, DefaultHashPolicy, TreeOptions{}); err == nil {
+		t.Error("expected an error when no paths are given")
+	}
+}
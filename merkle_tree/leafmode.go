@@ -0,0 +1,51 @@
+package merkletree
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+)
+
+// LeafMode selects what a leaf commits to besides the file content.
+type LeafMode string
+
+const (
+	// LeafContent hashes the file content only, so renaming a file or
+	// swapping the contents of two neighbouring files can keep the root.
+	// Trees saved without a leaf mode use it.
+	LeafContent LeafMode = "content"
+	// LeafPath also commits to the file's manifest path.
+	LeafPath LeafMode = "path"
+	// LeafPathAndMode commits to the manifest path and the file mode.
+	LeafPathAndMode LeafMode = "path+mode"
+)
+
+// ParseLeafMode returns the leaf mode with the given name; an empty name
+// means LeafContent.
+func ParseLeafMode(name string) (LeafMode, error) {
+	switch mode := LeafMode(name); mode {
+	case "":
+		return LeafContent, nil
+	case LeafContent, LeafPath, LeafPathAndMode:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown leaf mode %q", name)
+	}
+}
+
+// LeafData returns the data a leaf is built from for a file with the given
+// manifest path, mode and content hash. For LeafContent that is the content
+// hash itself; otherwise it is the length prefixed path, the mode for
+// LeafPathAndMode, and then the content hash.
+func (l LeafMode) LeafData(path string, mode os.FileMode, contentHash []byte) []byte {
+	if l != LeafPath && l != LeafPathAndMode {
+		return contentHash
+	}
+
+	data := binary.AppendUvarint(nil, uint64(len(path)))
+	data = append(data, path...)
+	if l == LeafPathAndMode {
+		data = binary.BigEndian.AppendUint32(data, uint32(mode))
+	}
+	return append(data, contentHash...)
+}
@@ -0,0 +1,67 @@
+package merkletree
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLeafModeDetectsRenames(t *testing.T) {
+	original := []HashResult{{Path: "a", Hash: []byte("1")}, {Path: "b", Hash: []byte("2")}}
+	renamed := []HashResult{{Path: "a", Hash: []byte("1")}, {Path: "c", Hash: []byte("2")}}
+	chmodded := []HashResult{{Path: "a", Hash: []byte("1")}, {Path: "b", Hash: []byte("2"), Mode: 0755}}
+
+	for _, tc := range []struct {
+		mode          LeafMode
+		rename, chmod bool
+	}{
+		{LeafContent, false, false},
+		{LeafPath, true, false},
+		{LeafPathAndMode, true, true},
+	} {
+		opts := TreeOptions{LeafMode: tc.mode}
+		base := BuildFromResults(original, opts)
+
+		if got := !base.Equal(BuildFromResults(renamed, opts)); got != tc.rename {
+			t.Errorf("%s: rename detected = %v, want %v", tc.mode, got, tc.rename)
+		}
+		if got := !base.Equal(BuildFromResults(chmodded, opts)); got != tc.chmod {
+			t.Errorf("%s: mode change detected = %v, want %v", tc.mode, got, tc.chmod)
+		}
+	}
+}
+
+func TestLeafModeProofsAndReload(t *testing.T) {
+	results := []HashResult{
+		{Path: "a", Hash: []byte("1"), Mode: 0644},
+		{Path: "dir/b", Hash: []byte("2"), Mode: 0755},
+		{Path: "dir/c", Hash: []byte("3"), Mode: 0600},
+	}
+	tree := BuildFromResults(results, TreeOptions{HashScheme: HashSchemePrefixed, LeafMode: LeafPathAndMode})
+
+	for i, result := range results {
+		proof, err := tree.ProofForPath(result.Path)
+		if err != nil {
+			t.Fatalf("ProofForPath(%s) failed: %v", result.Path, err)
+		}
+		leaf := proof.HashScheme.LeafHash(proof.LeafMode.LeafData(proof.Path, result.Mode, result.Hash))
+		if !VerifyProof(leaf, proof, tree.Root.Hash) {
+			t.Errorf("proof for leaf %d does not verify", i)
+		}
+		moved := proof.HashScheme.LeafHash(proof.LeafMode.LeafData("elsewhere", result.Mode, result.Hash))
+		if VerifyProof(moved, proof, tree.Root.Hash) {
+			t.Errorf("proof for leaf %d verified under another path", i)
+		}
+	}
+
+	filename := filepath.Join(t.TempDir(), "tree.json")
+	if err := tree.Save(filename); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(filename)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.LeafMode != LeafPathAndMode || !loaded.Equal(tree) {
+		t.Errorf("loaded tree lost its leaf mode: %q %s", loaded.LeafMode, loaded.RootHash)
+	}
+}
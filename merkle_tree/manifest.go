@@ -0,0 +1,125 @@
+package merkletree
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ManifestEntry records what a single leaf was built from.
+type ManifestEntry struct {
+	Path string      `json:"path"` // canonical path, see canonicalPath
+	Size int64       `json:"size"`
+	Mode os.FileMode `json:"mode"`
+	Hash string      `json:"hash"` // hex sha256 of the file content
+}
+
+func newManifestEntry(result HashResult) ManifestEntry {
+	path := result.Path
+	if path == "" {
+		path = filepath.ToSlash(result.File)
+	}
+	return ManifestEntry{
+		Path: path,
+		Size: result.Size,
+		Mode: result.Mode,
+		Hash: hex.EncodeToString(result.Hash),
+	}
+}
+
+// rebuildFromManifest rebuilds a loaded tree from its manifest and checks
+// the result against the root hash that was saved with it.
+func rebuildFromManifest(loaded *MerkleTree) (*MerkleTree, error) {
+	results := make([]HashResult, 0, len(loaded.Manifest))
+	for _, entry := range loaded.Manifest {
+		hash, err := hex.DecodeString(entry.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hash for %s: %v", entry.Path, err)
+		}
+		results = append(results, HashResult{Path: entry.Path, Hash: hash, Size: entry.Size, Mode: entry.Mode})
+	}
+
+	tree := BuildFromResults(results, TreeOptions{
+		HashScheme:      loaded.HashScheme,
+		OddNodeStrategy: loaded.OddNodeStrategy,
+		LeafMode:        loaded.LeafMode,
+	})
+	if tree == nil {
+		return nil, fmt.Errorf("could not rebuild tree from manifest")
+	}
+	if loaded.RootHash != "" && tree.RootHash != loaded.RootHash {
+		return nil, fmt.Errorf("manifest does not match root hash %s", loaded.RootHash)
+	}
+
+	tree.CreatedAt = loaded.CreatedAt
+
+	return tree, nil
+}
+
+// setRelativePaths fills in the canonical path of each result relative to
+// base and sorts the results by it, so the leaf order does not depend on
+// where the files live on this host.
+func setRelativePaths(results []HashResult, base string) error {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return err
+	}
+
+	for i := range results {
+		results[i].Path, err = canonicalPath(absBase, results[i].File)
+		if err != nil {
+			return err
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+	for i := 1; i < len(results); i++ {
+		if results[i].Path == results[i-1].Path {
+			return fmt.Errorf("%s and %s share the canonical path %s", results[i-1].File, results[i].File, results[i].Path)
+		}
+	}
+
+	return nil
+}
+
+// canonicalPath returns file relative to base, slash separated and in
+// Unicode NFC so the same name spelled differently by two filesystems
+// compares equal.
+func canonicalPath(base, file string) (string, error) {
+	rel, err := filepath.Rel(base, file)
+	if err != nil {
+		return "", err
+	}
+	return norm.NFC.String(filepath.ToSlash(rel)), nil
+}
+
+// commonDir returns the deepest directory that contains all of paths.
+func commonDir(paths []string) string {
+	if len(paths) == 0 {
+		return "."
+	}
+
+	dir := filepath.Dir(paths[0])
+	for _, path := range paths[1:] {
+		for {
+			rel, err := filepath.Rel(dir, path)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				break
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+
+	return dir
+}
@@ -0,0 +1,139 @@
+package merkletree
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestManifestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "a.txt", []byte("alpha"))
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatalf("Failed to create subdirectory: %v", err)
+	}
+	writeTestFile(t, dir, "sub/b.txt", []byte("bravo!"))
+
+	results, err := HashDirectory(dir, DefaultHashPolicy)
+	if err != nil {
+		t.Fatalf("hashFilesInDirectory failed: %v", err)
+	}
+	tree := BuildFromResults(results, TreeOptions{})
+
+	if tree.Manifest[0].Path != "a.txt" || tree.Manifest[1].Path != "sub/b.txt" {
+		t.Errorf("unexpected manifest paths: %+v", tree.Manifest)
+	}
+	if tree.Manifest[1].Size != 6 || !tree.Manifest[1].Mode.IsRegular() {
+		t.Errorf("unexpected manifest entry: %+v", tree.Manifest[1])
+	}
+
+	filename := filepath.Join(t.TempDir(), "tree.json")
+	if err := tree.Save(filename); err != nil {
+		t.Fatalf("SaveToFile failed: %v", err)
+	}
+	loaded, err := Load(filename)
+	if err != nil {
+		t.Fatalf("LoadMerkleTreeFromFile failed: %v", err)
+	}
+
+	if loaded.RootHash != tree.RootHash {
+		t.Errorf("root hash changed on reload: %s != %s", loaded.RootHash, tree.RootHash)
+	}
+	if !reflect.DeepEqual(loaded.Manifest, tree.Manifest) {
+		t.Errorf("manifest changed on reload: %+v != %+v", loaded.Manifest, tree.Manifest)
+	}
+	if _, err := loaded.ProofForPath("sub/b.txt"); err != nil {
+		t.Errorf("ProofForPath on loaded tree failed: %v", err)
+	}
+}
+
+func TestLoadRejectsTamperedManifest(t *testing.T) {
+	tree := BuildFromResults([]HashResult{
+		{File: "a", Hash: []byte("1")},
+		{File: "b", Hash: []byte("2")},
+	}, TreeOptions{})
+	tree.Manifest[1].Hash = "00"
+
+	jsonData, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	filename := filepath.Join(t.TempDir(), "tree.json")
+	if err := os.WriteFile(filename, jsonData, 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if _, err := Load(filename); err == nil {
+		t.Error("expected an error for a manifest that does not match the root hash")
+	}
+}
+
+func TestCommonDir(t *testing.T) {
+	root := string(filepath.Separator)
+	paths := []string{
+		filepath.Join(root, "srv", "data", "a", "x.txt"),
+		filepath.Join(root, "srv", "data", "b", "y.txt"),
+		filepath.Join(root, "srv", "data", "z.txt"),
+	}
+	if got, want := commonDir(paths), filepath.Join(root, "srv", "data"); got != want {
+		t.Errorf("commonDir = %s, want %s", got, want)
+	}
+}
+
+func TestCanonicalPathsIgnoreHashedRoot(t *testing.T) {
+	var roots []string
+	for _, base := range []string{t.TempDir(), filepath.Join(t.TempDir(), "nested", "copy")} {
+		if err := os.MkdirAll(filepath.Join(base, "sub"), 0755); err != nil {
+			t.Fatalf("Failed to create subdirectory: %v", err)
+		}
+		writeTestFile(t, base, "a.txt", []byte("alpha"))
+		writeTestFile(t, base, "sub/b.txt", []byte("bravo"))
+
+		tree, err := BuildFromPaths([]string{base}, DefaultHashPolicy, TreeOptions{LeafMode: LeafPath})
+		if err != nil {
+			t.Fatalf("BuildFromPaths failed: %v", err)
+		}
+		if tree.Manifest[1].Path != "sub/b.txt" {
+			t.Errorf("unexpected manifest paths: %+v", tree.Manifest)
+		}
+		roots = append(roots, tree.RootHash)
+	}
+
+	if roots[0] != roots[1] {
+		t.Errorf("root hash depends on the hashed root: %s != %s", roots[0], roots[1])
+	}
+}
+
+func TestCanonicalPathsAreNFC(t *testing.T) {
+	dir := t.TempDir()
+	// "e" followed by a combining acute accent sorts before "f.txt" as is,
+	// but after it once composed to U+00E9.
+	writeTestFile(t, dir, "e\u0301.txt", []byte("decomposed"))
+	writeTestFile(t, dir, "f.txt", []byte("plain"))
+
+	results, err := HashDirectory(dir, DefaultHashPolicy)
+	if err != nil {
+		t.Fatalf("HashDirectory failed: %v", err)
+	}
+	if results[0].Path != "f.txt" || results[1].Path != "\u00e9.txt" {
+		t.Errorf("paths not canonical or not ordered by canonical path: %q, %q", results[0].Path, results[1].Path)
+	}
+
+	tree := BuildFromResults(results, TreeOptions{})
+	if _, err := tree.ProofForPath("e\u0301.txt"); err != nil {
+		t.Errorf("ProofForPath with a decomposed name failed: %v", err)
+	}
+}
+
+func TestSetRelativePathsRejectsCollisions(t *testing.T) {
+	base := string(filepath.Separator) + "srv"
+	results := []HashResult{
+		{File: filepath.Join(base, "\u00e9.txt")},
+		{File: filepath.Join(base, "e\u0301.txt")},
+	}
+	if err := setRelativePaths(results, base); err == nil {
+		t.Error("expected an error for two files with the same canonical path")
+	}
+}
@@ -0,0 +1,222 @@
+// Package merkletree builds Merkle trees over files and directories, saves
+// and reloads them as JSON, diffs them and produces inclusion proofs for
+// single files.
+package merkletree
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// MerkleNode is a node of a MerkleTree. Leaves have no children.
+type MerkleNode struct {
+	Left  *MerkleNode `json:"left,omitempty"`
+	Right *MerkleNode `json:"right,omitempty"`
+	Hash  []byte      `json:"hash"`
+}
+
+// MerkleTree is a Merkle tree over a list of leaf hashes, usually the
+// sha256 of a set of files.
+type MerkleTree struct {
+	Root      *MerkleNode `json:"root"`
+	CreatedAt time.Time   `json:"created_at"`
+	FileCount int         `json:"file_count"`
+	RootHash  string      `json:"root_hash"`
+	// HashScheme is how the tree was hashed; empty means HashSchemeLegacy.
+	HashScheme HashScheme `json:"hash_scheme,omitempty"`
+	// OddNodeStrategy is how odd levels were closed; empty means
+	// OddNodeDuplicate.
+	OddNodeStrategy OddNodeStrategy `json:"odd_node_strategy,omitempty"`
+	// LeafMode is what the leaves commit to; empty means LeafContent.
+	LeafMode LeafMode `json:"leaf_mode,omitempty"`
+
+	// Manifest describes the file behind each leaf, in leaf order. It is
+	// only known for trees built from files.
+	Manifest []ManifestEntry `json:"manifest,omitempty"`
+
+	files  []string // absolute path of each leaf on the host that built it
+	leaves []*MerkleNode
+}
+
+// ToJSON serializes the tree in the format written by Save.
+func (m *MerkleTree) ToJSON() ([]byte, error) {
+	if m.Root != nil {
+		m.RootHash = hex.EncodeToString(m.Root.Hash)
+	}
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// Save writes the tree to filename as JSON.
+func (m *MerkleTree) Save(filename string) error {
+	jsonData, err := m.ToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to serialize tree: %v", err)
+	}
+
+	return os.WriteFile(filename, jsonData, 0644)
+}
+
+// Load reads a tree written by Save. Trees saved with a manifest are
+// rebuilt from it and checked against their saved root hash.
+func Load(filename string) (*MerkleTree, error) {
+	jsonData, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	var tree MerkleTree
+	err = json.Unmarshal(jsonData, &tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %v", err)
+	}
+
+	tree.HashScheme, err = ParseHashScheme(string(tree.HashScheme))
+	if err != nil {
+		return nil, err
+	}
+	tree.OddNodeStrategy, err = ParseOddNodeStrategy(string(tree.OddNodeStrategy))
+	if err != nil {
+		return nil, err
+	}
+	tree.LeafMode, err = ParseLeafMode(string(tree.LeafMode))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tree.Manifest) > 0 {
+		return rebuildFromManifest(&tree)
+	}
+
+	return &tree, nil
+}
+
+// Equal reports whether both trees have the same root hash.
+func (m *MerkleTree) Equal(other *MerkleTree) bool {
+	return m.RootHash == other.RootHash
+}
+
+// NewMerkleNode hashes the children and data of a node with the legacy
+// scheme, see HashSchemeLegacy.
+func NewMerkleNode(left, right *MerkleNode, data []byte) *MerkleNode {
+	hash := sha256.New()
+	if left != nil {
+		hash.Write(left.Hash)
+	}
+	if right != nil {
+		hash.Write(right.Hash)
+	}
+	if data != nil {
+		hash.Write(data)
+	}
+	hashValue := hash.Sum(nil)
+
+	return &MerkleNode{Left: left, Right: right, Hash: hashValue}
+}
+
+// TreeOptions controls how a tree is hashed. The zero value builds the
+// same trees as earlier versions.
+type TreeOptions struct {
+	HashScheme      HashScheme
+	OddNodeStrategy OddNodeStrategy
+	// LeafMode only applies to trees built from files, see BuildFromResults.
+	LeafMode LeafMode
+}
+
+func (o TreeOptions) withDefaults() TreeOptions {
+	if o.HashScheme == "" {
+		o.HashScheme = HashSchemeLegacy
+	}
+	if o.OddNodeStrategy == "" {
+		o.OddNodeStrategy = OddNodeDuplicate
+	}
+	if o.LeafMode == "" {
+		o.LeafMode = LeafContent
+	}
+	return o
+}
+
+// Build builds a tree whose leaves hash the given data, in order. It
+// returns nil when data is empty. The data is used as is whatever the
+// LeafMode.
+func Build(data [][]byte, opts TreeOptions) *MerkleTree {
+	opts = opts.withDefaults()
+
+	var nodes []*MerkleNode
+	for _, d := range data {
+		nodes = append(nodes, opts.HashScheme.newLeaf(d))
+	}
+
+	root := opts.OddNodeStrategy.buildRoot(nodes, opts.HashScheme)
+	if root == nil {
+		return nil
+	}
+
+	tree := &MerkleTree{
+		Root:            root,
+		CreatedAt:       time.Now(),
+		FileCount:       len(data),
+		HashScheme:      opts.HashScheme,
+		OddNodeStrategy: opts.OddNodeStrategy,
+		LeafMode:        opts.LeafMode,
+		leaves:          nodes,
+	}
+
+	// Set the root hash string
+	if tree.Root != nil {
+		tree.RootHash = hex.EncodeToString(tree.Root.Hash)
+	}
+
+	return tree
+}
+
+// BuildFromResults builds a tree from hashed files and records a manifest
+// entry for every leaf. Each leaf is built from opts.LeafMode.LeafData.
+func BuildFromResults(results []HashResult, opts TreeOptions) *MerkleTree {
+	opts = opts.withDefaults()
+
+	data := make([][]byte, 0, len(results))
+	manifest := make([]ManifestEntry, 0, len(results))
+	files := make([]string, 0, len(results))
+	for _, result := range results {
+		entry := newManifestEntry(result)
+		data = append(data, opts.LeafMode.LeafData(entry.Path, entry.Mode, result.Hash))
+		manifest = append(manifest, entry)
+		files = append(files, result.File)
+	}
+
+	tree := Build(data, opts)
+	if tree != nil {
+		tree.Manifest = manifest
+		tree.files = files
+	}
+	return tree
+}
+
+// BuildFromPaths hashes the files under paths and builds a tree from them.
+// A single path is walked as a directory; several paths must all be files.
+func BuildFromPaths(paths []string, policy HashPolicy, opts TreeOptions) (*MerkleTree, error) {
+	var results []HashResult
+	var err error
+
+	switch {
+	case len(paths) > 1:
+		results, err = HashFiles(paths, policy)
+	case len(paths) == 1:
+		results, err = HashDirectory(paths[0], policy)
+	default:
+		return nil, fmt.Errorf("no files provided")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tree := BuildFromResults(results, opts)
+	if tree == nil {
+		return nil, fmt.Errorf("could not build Merkle Tree")
+	}
+	return tree, nil
+}
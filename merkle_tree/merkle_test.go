@@ -1,4 +1,4 @@
-package main
+package merkletree
 
 import (
 	"context"
@@ -103,6 +103,13 @@ func parseBenchmarkArgs() BenchmarkConfig {
 	return config
 }
 
+// hashPolicy is DefaultHashPolicy with the configured timeout
+func (config BenchmarkConfig) hashPolicy() HashPolicy {
+	policy := DefaultHashPolicy
+	policy.Timeout = config.Timeout
+	return policy
+}
+
 // Benchmark: File hashing operations
 func BenchmarkHashFiles(b *testing.B) {
 	config := parseBenchmarkArgs()
@@ -117,7 +124,7 @@ func BenchmarkHashFiles(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		_, err := hashFilesWithTimeout(files, config.Timeout)
+		_, err := hashFilesWithPolicy(files, config.hashPolicy())
 		if err != nil {
 			b.Fatalf("hashFiles failed: %v", err)
 		}
@@ -145,9 +152,9 @@ func BenchmarkHashSingleFile(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		ctx := context.Background()
-		_, err := hashFile(ctx, tempFile.Name())
+		_, err := HashFile(ctx, tempFile.Name(), config.hashPolicy())
 		if err != nil {
-			b.Fatalf("hashFile failed: %v", err)
+			b.Fatalf("HashFile failed: %v", err)
 		}
 	}
 }
@@ -162,7 +169,7 @@ func BenchmarkBuildMerkleTree(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		tree := buildMerkleTree(data)
+		tree := Build(data, TreeOptions{})
 		if tree == nil {
 			b.Fatal("buildMerkleTree returned nil")
 		}
@@ -180,13 +187,13 @@ func BenchmarkEndToEnd(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		// Hash files
-		hashes, err := hashFilesWithTimeout(files, config.Timeout)
+		hashes, err := hashFilesWithPolicy(files, config.hashPolicy())
 		if err != nil {
 			b.Fatalf("hashFiles failed: %v", err)
 		}
 
 		// Build Merkle tree
-		tree := buildMerkleTree(hashes)
+		tree := BuildFromResults(hashes, TreeOptions{})
 		if tree == nil {
 			b.Fatal("buildMerkleTree returned nil")
 		}
@@ -246,7 +253,7 @@ func benchmarkByFileSize(b *testing.B, fileSize int) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		_, err := hashFilesWithTimeout(files, config.Timeout)
+		_, err := hashFilesWithPolicy(files, config.hashPolicy())
 		if err != nil {
 			b.Fatalf("hashFiles failed: %v", err)
 		}
@@ -271,7 +278,7 @@ func benchmarkByFileCount(b *testing.B, numFiles int) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		_, err := hashFilesWithTimeout(files, config.Timeout)
+		_, err := hashFilesWithPolicy(files, config.hashPolicy())
 		if err != nil {
 			b.Fatalf("hashFiles failed: %v", err)
 		}
@@ -318,13 +325,13 @@ func benchmarkByWorkerCount(b *testing.B, workers int) {
 						if !ok {
 							return
 						}
-						hash, err := hashFile(ctx, job)
+						result, err := HashFile(ctx, job, config.hashPolicy())
 						if err != nil {
 							errors <- err
 							cancel()
 							return
 						}
-						results <- HashResult{File: job, Hash: hash}
+						results <- result
 					case <-ctx.Done():
 						return
 					}
@@ -403,7 +410,7 @@ func BenchmarkMerkleTreeMemory(b *testing.B) {
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
-		tree := buildMerkleTree(data)
+		tree := Build(data, TreeOptions{})
 		if tree == nil {
 			b.Fatal("buildMerkleTree returned nil")
 		}
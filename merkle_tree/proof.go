@@ -0,0 +1,165 @@
+package merkletree
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ProofPosition tells the verifier on which side of the running hash a
+// sibling hash has to be placed.
+type ProofPosition string
+
+const (
+	PositionLeft  ProofPosition = "left"
+	PositionRight ProofPosition = "right"
+)
+
+// ProofStep is one sibling hash on the way from a leaf to the root.
+type ProofStep struct {
+	Hash     []byte        `json:"hash"`
+	Position ProofPosition `json:"position"`
+}
+
+// MerkleProof is the audit path from a single leaf up to the root.
+type MerkleProof struct {
+	LeafIndex int    `json:"leaf_index"`
+	Path      string `json:"path,omitempty"`
+	LeafHash  []byte `json:"leaf_hash"`
+	RootHash  string `json:"root_hash"`
+	// HashScheme is the scheme of the tree the proof was taken from.
+	HashScheme HashScheme `json:"hash_scheme,omitempty"`
+	// LeafMode tells the verifier how to turn a file into LeafHash.
+	LeafMode LeafMode    `json:"leaf_mode,omitempty"`
+	Steps    []ProofStep `json:"steps"`
+}
+
+// ProofForIndex returns the inclusion proof for the leaf at index.
+func (m *MerkleTree) ProofForIndex(index int) (*MerkleProof, error) {
+	leaves := m.leafNodes()
+	if index < 0 || index >= len(leaves) {
+		return nil, fmt.Errorf("leaf index %d out of range [0, %d)", index, len(leaves))
+	}
+
+	proof := &MerkleProof{
+		LeafIndex:  index,
+		LeafHash:   leaves[index].Hash,
+		RootHash:   hex.EncodeToString(m.Root.Hash),
+		HashScheme: m.HashScheme,
+		LeafMode:   m.LeafMode,
+	}
+	if index < len(m.Manifest) {
+		proof.Path = m.Manifest[index].Path
+	}
+
+	// Walk down from the root, recording the sibling of every node passed
+	pos := index
+	node := m.Root
+	for !isLeaf(node) {
+		first, second := m.OddNodeStrategy.children(node)
+		inFirst := pos < leafCount(first)
+
+		// Padding can make both children the same node, so the side is
+		// worked out from the strategy rather than by comparing pointers
+		if inFirst == m.OddNodeStrategy.earlierOnRight() {
+			proof.Steps = append(proof.Steps, ProofStep{Hash: node.Left.Hash, Position: PositionLeft})
+		} else {
+			proof.Steps = append(proof.Steps, ProofStep{Hash: node.Right.Hash, Position: PositionRight})
+		}
+
+		if inFirst {
+			node = first
+		} else {
+			pos -= leafCount(first)
+			node = second
+		}
+	}
+	slices.Reverse(proof.Steps)
+
+	return proof, nil
+}
+
+// ProofForPath returns the inclusion proof for the leaf built from path,
+// given either as its manifest path or as a path on the building host.
+func (m *MerkleTree) ProofForPath(path string) (*MerkleProof, error) {
+	for i, entry := range m.Manifest {
+		if entry.Path == norm.NFC.String(filepath.ToSlash(path)) {
+			return m.ProofForIndex(i)
+		}
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	for i, file := range m.files {
+		if file == absPath {
+			return m.ProofForIndex(i)
+		}
+	}
+
+	return nil, fmt.Errorf("file %s is not part of the tree", path)
+}
+
+// VerifyProof recomputes the root from leafHash and the proof steps and
+// reports whether it matches rootHash.
+func VerifyProof(leafHash []byte, proof *MerkleProof, rootHash []byte) bool {
+	if proof == nil {
+		return false
+	}
+
+	scheme, err := ParseHashScheme(string(proof.HashScheme))
+	if err != nil {
+		return false
+	}
+
+	current := leafHash
+	for _, step := range proof.Steps {
+		switch step.Position {
+		case PositionLeft:
+			current = scheme.NodeHash(step.Hash, current)
+		case PositionRight:
+			current = scheme.NodeHash(current, step.Hash)
+		default:
+			return false
+		}
+	}
+
+	return bytes.Equal(current, rootHash)
+}
+
+// leafNodes returns the leaves of the tree in order. Trees loaded from JSON
+// do not keep their leaves, so they are recovered by walking the nodes.
+func (m *MerkleTree) leafNodes() []*MerkleNode {
+	if m.leaves != nil {
+		return m.leaves
+	}
+
+	var leaves []*MerkleNode
+	var walk func(node *MerkleNode)
+	walk = func(node *MerkleNode) {
+		if node == nil {
+			return
+		}
+		if node.Left == nil && node.Right == nil {
+			leaves = append(leaves, node)
+			return
+		}
+		first, second := m.OddNodeStrategy.children(node)
+		walk(first)
+		walk(second)
+	}
+	walk(m.Root)
+
+	// Drop the copies added to pad odd levels
+	if len(leaves) > m.FileCount {
+		leaves = leaves[:m.FileCount]
+	}
+
+	m.leaves = leaves
+	return leaves
+}
@@ -0,0 +1,92 @@
+package merkletree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProofForIndexVerifies(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		data := createDeterministicData(n, 32)
+		tree := Build(data, TreeOptions{})
+
+		for i := range n {
+			proof, err := tree.ProofForIndex(i)
+			if err != nil {
+				t.Fatalf("n=%d: ProofForIndex(%d) failed: %v", n, i, err)
+			}
+			if !VerifyProof(HashSchemeLegacy.LeafHash(data[i]), proof, tree.Root.Hash) {
+				t.Errorf("n=%d: proof for leaf %d does not verify", n, i)
+			}
+			if VerifyProof(HashSchemeLegacy.LeafHash([]byte("other")), proof, tree.Root.Hash) {
+				t.Errorf("n=%d: proof for leaf %d verified a foreign leaf", n, i)
+			}
+		}
+	}
+}
+
+func TestProofForIndexOutOfRange(t *testing.T) {
+	tree := Build(createDeterministicData(3, 32), TreeOptions{})
+
+	if _, err := tree.ProofForIndex(3); err == nil {
+		t.Error("expected an error for an index past the last leaf")
+	}
+	if _, err := tree.ProofForIndex(-1); err == nil {
+		t.Error("expected an error for a negative index")
+	}
+}
+
+func TestProofFromLoadedTree(t *testing.T) {
+	data := createDeterministicData(5, 32)
+	tree := Build(data, TreeOptions{})
+
+	filename := filepath.Join(t.TempDir(), "tree.json")
+	if err := tree.Save(filename); err != nil {
+		t.Fatalf("SaveToFile failed: %v", err)
+	}
+	loaded, err := Load(filename)
+	if err != nil {
+		t.Fatalf("LoadMerkleTreeFromFile failed: %v", err)
+	}
+
+	for i := range data {
+		proof, err := loaded.ProofForIndex(i)
+		if err != nil {
+			t.Fatalf("ProofForIndex(%d) failed: %v", i, err)
+		}
+		if !VerifyProof(HashSchemeLegacy.LeafHash(data[i]), proof, tree.Root.Hash) {
+			t.Errorf("proof for leaf %d of loaded tree does not verify", i)
+		}
+	}
+}
+
+func TestProofForPath(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	results, err := HashDirectory(dir, DefaultHashPolicy)
+	if err != nil {
+		t.Fatalf("hashFilesInDirectory failed: %v", err)
+	}
+	tree := BuildFromResults(results, TreeOptions{})
+
+	proof, err := tree.ProofForPath(filepath.Join(dir, "b.txt"))
+	if err != nil {
+		t.Fatalf("ProofForPath failed: %v", err)
+	}
+	if proof.LeafIndex != 1 {
+		t.Errorf("expected leaf index 1, got %d", proof.LeafIndex)
+	}
+	if !VerifyProof(HashSchemeLegacy.LeafHash(results[1].Hash), proof, tree.Root.Hash) {
+		t.Error("proof for b.txt does not verify")
+	}
+
+	if _, err := tree.ProofForPath(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("expected an error for a file outside the tree")
+	}
+}
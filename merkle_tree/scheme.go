@@ -0,0 +1,68 @@
+package merkletree
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// HashScheme selects how leaf and interior node hashes are computed.
+type HashScheme string
+
+const (
+	// HashSchemeLegacy hashes leaves and interior nodes the same way, as
+	// plain sha256 of the concatenated input. Trees saved without a scheme
+	// use it.
+	HashSchemeLegacy HashScheme = "legacy"
+	// HashSchemePrefixed prepends 0x00 to leaf input and 0x01 to interior
+	// node input (RFC 6962), so an interior node can't pass for a leaf.
+	HashSchemePrefixed HashScheme = "prefixed"
+)
+
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// ParseHashScheme returns the scheme with the given name; an empty name
+// means HashSchemeLegacy.
+func ParseHashScheme(name string) (HashScheme, error) {
+	switch scheme := HashScheme(name); scheme {
+	case "":
+		return HashSchemeLegacy, nil
+	case HashSchemeLegacy, HashSchemePrefixed:
+		return scheme, nil
+	default:
+		return "", fmt.Errorf("unknown hash scheme %q", name)
+	}
+}
+
+// LeafHash returns the hash a leaf node holds for the given file hash.
+func (s HashScheme) LeafHash(data []byte) []byte {
+	if s != HashSchemePrefixed {
+		return NewMerkleNode(nil, nil, data).Hash
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte{leafPrefix})
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+// NodeHash returns the hash of an interior node with the given children.
+func (s HashScheme) NodeHash(left, right []byte) []byte {
+	hash := sha256.New()
+	if s == HashSchemePrefixed {
+		hash.Write([]byte{nodePrefix})
+	}
+	hash.Write(left)
+	hash.Write(right)
+	return hash.Sum(nil)
+}
+
+func (s HashScheme) newLeaf(data []byte) *MerkleNode {
+	return &MerkleNode{Hash: s.LeafHash(data)}
+}
+
+func (s HashScheme) newNode(left, right *MerkleNode) *MerkleNode {
+	return &MerkleNode{Left: left, Right: right, Hash: s.NodeHash(left.Hash, right.Hash)}
+}
@@ -0,0 +1,96 @@
+package merkletree
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// forgeInteriorLeaves returns leaf data whose legacy leaf hashes equal the
+// first interior level of a tree over data.
+func forgeInteriorLeaves(scheme HashScheme, data [][]byte) [][]byte {
+	var forged [][]byte
+	for i := 1; i < len(data); i += 2 {
+		forged = append(forged, append(scheme.LeafHash(data[i]), scheme.LeafHash(data[i-1])...))
+	}
+	return forged
+}
+
+func TestLegacySchemeMatchesNewMerkleNode(t *testing.T) {
+	data := createDeterministicData(2, 32)
+	tree := Build(data, TreeOptions{})
+
+	want := NewMerkleNode(NewMerkleNode(nil, nil, data[1]), NewMerkleNode(nil, nil, data[0]), nil)
+	if !bytes.Equal(tree.Root.Hash, want.Hash) {
+		t.Errorf("legacy root changed: got %x, want %x", tree.Root.Hash, want.Hash)
+	}
+	if tree.HashScheme != HashSchemeLegacy {
+		t.Errorf("expected the legacy scheme by default, got %q", tree.HashScheme)
+	}
+}
+
+func TestPrefixedSchemeStopsInteriorNodeForgery(t *testing.T) {
+	data := createDeterministicData(4, 32)
+
+	legacy := Build(data, TreeOptions{})
+	forged := Build(forgeInteriorLeaves(HashSchemeLegacy, data), TreeOptions{})
+	if legacy.RootHash != forged.RootHash {
+		t.Fatal("expected interior nodes to pass as leaves under the legacy scheme")
+	}
+
+	opts := TreeOptions{HashScheme: HashSchemePrefixed}
+	prefixed := Build(data, opts)
+	forged = Build(forgeInteriorLeaves(HashSchemePrefixed, data), opts)
+	if prefixed.RootHash == forged.RootHash {
+		t.Error("interior nodes passed as leaves under the prefixed scheme")
+	}
+}
+
+func TestPrefixedSchemeProofs(t *testing.T) {
+	data := createDeterministicData(5, 32)
+	tree := Build(data, TreeOptions{HashScheme: HashSchemePrefixed})
+
+	for i := range data {
+		proof, err := tree.ProofForIndex(i)
+		if err != nil {
+			t.Fatalf("ProofForIndex(%d) failed: %v", i, err)
+		}
+		if !VerifyProof(HashSchemePrefixed.LeafHash(data[i]), proof, tree.Root.Hash) {
+			t.Errorf("proof for leaf %d does not verify", i)
+		}
+		if VerifyProof(HashSchemeLegacy.LeafHash(data[i]), proof, tree.Root.Hash) {
+			t.Errorf("proof for leaf %d verified a legacy leaf hash", i)
+		}
+	}
+}
+
+func TestHashSchemeIsSaved(t *testing.T) {
+	results := []HashResult{{File: "a", Hash: []byte("1")}, {File: "b", Hash: []byte("2")}}
+	tree := BuildFromResults(results, TreeOptions{HashScheme: HashSchemePrefixed})
+
+	filename := filepath.Join(t.TempDir(), "tree.json")
+	if err := tree.Save(filename); err != nil {
+		t.Fatalf("SaveToFile failed: %v", err)
+	}
+	loaded, err := Load(filename)
+	if err != nil {
+		t.Fatalf("LoadMerkleTreeFromFile failed: %v", err)
+	}
+	if loaded.HashScheme != HashSchemePrefixed || loaded.RootHash != tree.RootHash {
+		t.Errorf("loaded tree lost its scheme: %q %s", loaded.HashScheme, loaded.RootHash)
+	}
+
+	jsonData, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	bad := strings.Replace(string(jsonData), `"prefixed"`, `"bogus"`, 1)
+	if err := os.WriteFile(filename, []byte(bad), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := Load(filename); err == nil {
+		t.Error("expected an error for an unknown hash scheme")
+	}
+}
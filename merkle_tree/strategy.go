@@ -0,0 +1,104 @@
+package merkletree
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// OddNodeStrategy selects what happens to the node left over when a level
+// has an odd number of nodes.
+type OddNodeStrategy string
+
+const (
+	// OddNodeDuplicate pairs the lone node with a copy of itself. This is
+	// the original construction; it lets [a, b, c] and [a, b, c, c] share a
+	// root (CVE-2012-2459). Trees saved without a strategy use it.
+	OddNodeDuplicate OddNodeStrategy = "duplicate"
+	// OddNodePromote moves the lone node up to the next level unchanged.
+	OddNodePromote OddNodeStrategy = "promote"
+	// OddNodeSplit builds the RFC 6962 tree, splitting n leaves into the
+	// largest power of two below n and the rest.
+	OddNodeSplit OddNodeStrategy = "split"
+)
+
+// ParseOddNodeStrategy returns the strategy with the given name; an empty
+// name means OddNodeDuplicate.
+func ParseOddNodeStrategy(name string) (OddNodeStrategy, error) {
+	switch strategy := OddNodeStrategy(name); strategy {
+	case "":
+		return OddNodeDuplicate, nil
+	case OddNodeDuplicate, OddNodePromote, OddNodeSplit:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown odd node strategy %q", name)
+	}
+}
+
+// earlierOnRight reports whether the earlier node of each pair is stored
+// in the Right field. The level-by-level builders hash the later node of a
+// pair first and keep it on the left; the split builder follows RFC 6962.
+func (s OddNodeStrategy) earlierOnRight() bool {
+	return s != OddNodeSplit
+}
+
+// children returns the children of node in leaf order.
+func (s OddNodeStrategy) children(node *MerkleNode) (first, second *MerkleNode) {
+	if s.earlierOnRight() {
+		return node.Right, node.Left
+	}
+	return node.Left, node.Right
+}
+
+// buildRoot combines the leaves into a single root node.
+func (s OddNodeStrategy) buildRoot(leaves []*MerkleNode, scheme HashScheme) *MerkleNode {
+	if len(leaves) == 0 {
+		return nil
+	}
+
+	switch s {
+	case OddNodeSplit:
+		return buildSplit(leaves, scheme)
+	case OddNodePromote:
+		return buildLevels(leaves, scheme, false)
+	default:
+		return buildLevels(leaves, scheme, true)
+	}
+}
+
+// buildLevels pairs nodes level by level until a single root remains. With
+// duplicate set, a lone node is paired with itself, otherwise it is
+// promoted to the next level as is.
+func buildLevels(leaves []*MerkleNode, scheme HashScheme, duplicate bool) *MerkleNode {
+	nodes := append([]*MerkleNode(nil), leaves...)
+	if duplicate && len(nodes)%2 != 0 {
+		nodes = append(nodes, nodes[len(nodes)-1])
+	}
+
+	for len(nodes) > 1 {
+		var newNodes []*MerkleNode
+		for i := 1; i < len(nodes); i += 2 {
+
+			newNode := scheme.newNode(nodes[i], nodes[i-1])
+			newNodes = append(newNodes, newNode)
+		}
+		if len(nodes)%2 != 0 {
+			newNodes = append(newNodes, nodes[len(nodes)-1])
+		}
+		if duplicate && len(newNodes)%2 != 0 && len(newNodes) > 1 {
+			newNodes = append(newNodes, newNodes[len(newNodes)-1])
+		}
+		nodes = newNodes
+	}
+
+	return nodes[0]
+}
+
+// buildSplit builds MTH(D[n]) from RFC 6962 section 2.1.
+func buildSplit(nodes []*MerkleNode, scheme HashScheme) *MerkleNode {
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+
+	k := 1 << (bits.Len(uint(len(nodes)-1)) - 1)
+	return scheme.newNode(buildSplit(nodes[:k], scheme), buildSplit(nodes[k:], scheme))
+}
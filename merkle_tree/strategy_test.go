@@ -0,0 +1,97 @@
+package merkletree
+
+import (
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+)
+
+var allStrategies = []OddNodeStrategy{OddNodeDuplicate, OddNodePromote, OddNodeSplit}
+
+func TestDuplicateStrategyCollision(t *testing.T) {
+	data := createDeterministicData(3, 32)
+	padded := append(data[:3:3], data[2])
+
+	for _, strategy := range allStrategies {
+		opts := TreeOptions{OddNodeStrategy: strategy}
+		a := Build(data, opts)
+		b := Build(padded, opts)
+
+		collides := a.RootHash == b.RootHash
+		if strategy == OddNodeDuplicate && !collides {
+			t.Error("expected [a,b,c] and [a,b,c,c] to collide under the duplicate strategy")
+		}
+		if strategy != OddNodeDuplicate && collides {
+			t.Errorf("[a,b,c] and [a,b,c,c] collide under the %s strategy", strategy)
+		}
+	}
+}
+
+func TestStrategyProofs(t *testing.T) {
+	for _, scheme := range []HashScheme{HashSchemeLegacy, HashSchemePrefixed} {
+		for _, strategy := range allStrategies {
+			opts := TreeOptions{HashScheme: scheme, OddNodeStrategy: strategy}
+			for n := 1; n <= 9; n++ {
+				data := createDeterministicData(n, 32)
+				tree := Build(data, opts)
+
+				for i := range n {
+					proof, err := tree.ProofForIndex(i)
+					if err != nil {
+						t.Fatalf("%s/%s n=%d: ProofForIndex(%d) failed: %v", scheme, strategy, n, i, err)
+					}
+					if !VerifyProof(scheme.LeafHash(data[i]), proof, tree.Root.Hash) {
+						t.Errorf("%s/%s n=%d: proof for leaf %d does not verify", scheme, strategy, n, i)
+					}
+				}
+			}
+		}
+	}
+}
+
+// Test vectors from the Certificate Transparency reference implementation
+func TestSplitStrategyMatchesRFC6962(t *testing.T) {
+	inputs := []string{"", "00", "10", "2021", "3031", "40414243", "5051525354555657", "606162636465666768696a6b6c6d6e6f"}
+	roots := []string{
+		"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+		"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+		"aeb6bcfe274b70a14fb067a5e5578264db0fa9b51af5e0ba159158f329e06e77",
+		"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+		"4e3bbb1f7b478dcfe71fb631631519a3bca12c9aefca1612bfce4c13a86264d4",
+		"76e67dadbcdf1e10e1b74ddc608abd2f98dfb16fbce75277b5232a127f2087ef",
+		"ddb89be403809e325750d3d263cd78929c2942b7942a34b77e122c9594a74c8c",
+		"5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328",
+	}
+
+	var data [][]byte
+	for i, input := range inputs {
+		leaf, _ := hex.DecodeString(input)
+		data = append(data, leaf)
+
+		tree := Build(data, TreeOptions{HashScheme: HashSchemePrefixed, OddNodeStrategy: OddNodeSplit})
+		if tree.RootHash != roots[i] {
+			t.Errorf("root of %d leaves = %s, want %s", i+1, tree.RootHash, roots[i])
+		}
+	}
+}
+
+func TestOddNodeStrategyIsSaved(t *testing.T) {
+	results := []HashResult{{File: "a", Hash: []byte("1")}, {File: "b", Hash: []byte("2")}, {File: "c", Hash: []byte("3")}}
+	tree := BuildFromResults(results, TreeOptions{OddNodeStrategy: OddNodePromote})
+
+	filename := filepath.Join(t.TempDir(), "tree.json")
+	if err := tree.Save(filename); err != nil {
+		t.Fatalf("SaveToFile failed: %v", err)
+	}
+	loaded, err := Load(filename)
+	if err != nil {
+		t.Fatalf("LoadMerkleTreeFromFile failed: %v", err)
+	}
+	if loaded.OddNodeStrategy != OddNodePromote || loaded.RootHash != tree.RootHash {
+		t.Errorf("loaded tree lost its strategy: %q %s", loaded.OddNodeStrategy, loaded.RootHash)
+	}
+
+	if _, err := ParseOddNodeStrategy("bogus"); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+}